@@ -4,11 +4,14 @@ import (
 	"context"
 	"sync"
 
+	"github.com/alfin-efendy/helper-go/audit"
 	"github.com/alfin-efendy/helper-go/config"
 	"github.com/alfin-efendy/helper-go/database"
+	"github.com/alfin-efendy/helper-go/eventbus"
 	"github.com/alfin-efendy/helper-go/logger"
 	"github.com/alfin-efendy/helper-go/otel"
 	"github.com/alfin-efendy/helper-go/server/restapi"
+	"github.com/alfin-efendy/helper-go/session"
 	"github.com/alfin-efendy/helper-go/storage"
 )
 
@@ -25,6 +28,9 @@ func init() {
 	defer span.End()
 
 	database.Init(ctx)
+	eventbus.Init(ctx)
+	audit.Init(ctx)
+	session.Init(ctx)
 	storage.Init(ctx)
 	restapi.Init(ctx)
 }
@@ -37,7 +43,17 @@ func Start(fn func()) {
 	go restapi.Run(ctx)
 
 	defer func() {
-		err := otel.Shutdown(ctx)
+		err := eventbus.Close()
+		if err != nil {
+			logger.Error(ctx, err)
+		}
+
+		err = audit.Close()
+		if err != nil {
+			logger.Error(ctx, err)
+		}
+
+		err = otel.Shutdown(ctx)
 		if err != nil {
 			logger.Error(ctx, err)
 		}
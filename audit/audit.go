@@ -0,0 +1,341 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/alfin-efendy/helper-go/config"
+	helperCtx "github.com/alfin-efendy/helper-go/context"
+	"github.com/alfin-efendy/helper-go/database"
+	"github.com/alfin-efendy/helper-go/logger"
+	"github.com/alfin-efendy/helper-go/otel"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+	OutcomeDenied  = "denied"
+
+	ActionLogin         = "auth.login"
+	ActionLogout        = "auth.logout"
+	ActionAuthenticate  = "auth.authenticate"
+	ActionAuthorize     = "auth.authorize"
+	ActionTokenIssue    = "token.issue"
+	ActionTokenValidate = "token.validate"
+	ActionDataCreate    = "data.create"
+	ActionDataUpdate    = "data.update"
+	ActionDataDelete    = "data.delete"
+)
+
+// queueSize is the number of chained events waiting for the sinks before Record drops new events
+const queueSize = 1024
+
+var (
+	auditorInstance Auditor
+
+	ErrAuditorClosed  = errors.New("auditor is closed")
+	ErrAuditQueueFull = errors.New("audit queue is full")
+)
+
+// AuditEvent is a single entry of the audit trail, Sequence, PrevHash and Hash chain it to the previous entry
+type AuditEvent struct {
+	Chain      string                 `json:"chain" gorm:"primaryKey;size:255"`
+	Sequence   uint64                 `json:"sequence" gorm:"primaryKey;autoIncrement:false"`
+	ID         string                 `json:"id" gorm:"size:36;uniqueIndex"`
+	Time       time.Time              `json:"time" gorm:"index"`
+	ActorId    string                 `json:"actorId" gorm:"index"`
+	ActorName  string                 `json:"actorName,omitempty"`
+	Realm      string                 `json:"realm,omitempty"`
+	Action     string                 `json:"action" gorm:"index"`
+	Resource   string                 `json:"resource,omitempty"`
+	ResourceId string                 `json:"resourceId,omitempty"`
+	Outcome    string                 `json:"outcome"`
+	Reason     string                 `json:"reason,omitempty"`
+	IP         string                 `json:"ip,omitempty"`
+	RequestId  string                 `json:"requestId,omitempty"`
+	TraceId    string                 `json:"traceId,omitempty"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty" gorm:"serializer:json;type:text"`
+	PrevHash   string                 `json:"prevHash"`
+	Hash       string                 `json:"hash"`
+}
+
+type Auditor interface {
+	Record(ctx context.Context, event AuditEvent) error
+	Close() error
+}
+
+type auditor struct {
+	chain string
+	sinks []Sink
+
+	// mu guards the head of the chain, the sinks are written outside of it
+	mu       sync.Mutex
+	sequence uint64
+	hash     string
+	closed   bool
+	dropped  uint64
+
+	queue chan queuedEvent
+	done  chan struct{}
+}
+
+type queuedEvent struct {
+	ctx   context.Context
+	event AuditEvent
+}
+
+// NewAuditor writes the events of the chain to every sink, in chain order and in the background
+func NewAuditor(chain string, sinks ...Sink) Auditor {
+	a := &auditor{
+		chain: chain,
+		sinks: sinks,
+		queue: make(chan queuedEvent, queueSize),
+		done:  make(chan struct{}),
+	}
+
+	go a.run()
+
+	return a
+}
+
+func Init(ctx context.Context) {
+	ctx, span := otel.Trace(ctx)
+	defer span.End()
+
+	conf := config.Config.Audit
+	if conf == nil {
+		logger.Warn(ctx, "❌ Audit configuration is not found")
+		return
+	}
+
+	chain := conf.Chain
+	if chain == "" {
+		chain, _ = os.Hostname()
+	}
+
+	sinkTypes := conf.Sinks
+	if len(sinkTypes) == 0 {
+		sinkTypes = []string{SinkFile}
+	}
+
+	var sinks []Sink
+	for _, sinkType := range sinkTypes {
+		switch sinkType {
+		case SinkFile:
+			sink, err := NewFileSink(conf)
+			if err != nil {
+				logger.Fatal(ctx, err, "❌ Failed to open audit file")
+				return
+			}
+			sinks = append(sinks, sink)
+		case SinkSql:
+			sqlClient := database.GetSqlClient()
+			if sqlClient == nil {
+				logger.Warn(ctx, "❌ Audit sql sink is disabled, sql client is not initialized")
+				continue
+			}
+
+			sink, err := NewSqlSink(ctx, sqlClient, conf.Table)
+			if err != nil {
+				logger.Fatal(ctx, err, "❌ Failed to migrate audit table")
+				return
+			}
+			sinks = append(sinks, sink)
+		case SinkRedis:
+			redisClient := database.GetRedisClient()
+			if redisClient == nil {
+				logger.Warn(ctx, "❌ Audit redis sink is disabled, redis client is not initialized")
+				continue
+			}
+			sinks = append(sinks, NewRedisSink(redisClient, conf.Stream))
+		default:
+			logger.Fatal(ctx, fmt.Errorf("audit sink %s is not supported", sinkType), "❌ Failed unsupported audit sink")
+			return
+		}
+	}
+
+	if len(sinks) == 0 {
+		logger.Warn(ctx, "❌ Audit is disabled, no sink is available")
+		return
+	}
+
+	a := NewAuditor(chain, sinks...).(*auditor)
+
+	// Continue the chain written before the restart
+	if err := a.resume(ctx); err != nil {
+		logger.Fatal(ctx, err, "❌ Failed to read the last audit event")
+		return
+	}
+
+	auditorInstance = a
+
+	logger.Info(ctx, "✅ Audit initialized")
+}
+
+// resume continues from the most advanced head found in the sinks
+func (a *auditor) resume(ctx context.Context) error {
+	for _, sink := range a.sinks {
+		reader, ok := sink.(headReader)
+		if !ok {
+			continue
+		}
+
+		sequence, hash, err := reader.Head(ctx, a.chain)
+		if err != nil {
+			return err
+		}
+
+		if sequence > a.sequence {
+			a.sequence = sequence
+			a.hash = hash
+		}
+	}
+	return nil
+}
+
+func (a *auditor) Record(ctx context.Context, event AuditEvent) error {
+	a.fill(ctx, &event)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.closed {
+		return ErrAuditorClosed
+	}
+
+	// Drop the event before chaining it rather than block the request on slow sinks,
+	// only Record sends to the queue so it cannot fill up between the check and the send
+	if len(a.queue) == cap(a.queue) {
+		a.dropped++
+		return fmt.Errorf("%w, %d events dropped", ErrAuditQueueFull, a.dropped)
+	}
+
+	event.Chain = a.chain
+	event.Sequence = a.sequence + 1
+	event.PrevHash = a.hash
+
+	hash, err := Hash(event)
+	if err != nil {
+		return err
+	}
+	event.Hash = hash
+
+	a.sequence = event.Sequence
+	a.hash = event.Hash
+
+	// Queued under the lock so the sinks receive the events in chain order,
+	// the request may be done before they are written
+	a.queue <- queuedEvent{ctx: context.WithoutCancel(ctx), event: event}
+
+	return nil
+}
+
+// run writes the queued events to every sink until the auditor is closed
+func (a *auditor) run() {
+	defer close(a.done)
+
+	for queued := range a.queue {
+		var errs error
+		written := false
+		for _, sink := range a.sinks {
+			if err := sink.Write(queued.ctx, queued.event); err != nil {
+				errs = errors.Join(errs, err)
+				continue
+			}
+			written = true
+		}
+
+		if errs == nil {
+			continue
+		}
+
+		// An event missing from every sink leaves a gap that Verify reports as a broken chain
+		message := fmt.Sprintf("Failed to write audit event %d of chain %s", queued.event.Sequence, queued.event.Chain)
+		if !written {
+			message += " to any sink"
+		}
+		logger.Error(queued.ctx, errs, message)
+	}
+}
+
+// fill completes the event with the actor, client and trace of the context
+func (a *auditor) fill(ctx context.Context, event *AuditEvent) {
+	if event.ID == "" {
+		event.ID = uuid.NewString()
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	// Keep the precision stored by SQL databases so the hash can be verified after reading it back
+	event.Time = event.Time.UTC().Truncate(time.Microsecond)
+
+	// Only the subject of a verified token identifies the actor, the user id may be forwarded by the caller
+	if event.ActorId == "" {
+		event.ActorId = helperCtx.GetSubject(ctx)
+	}
+	if event.ActorName == "" {
+		event.ActorName = helperCtx.GetFullName(ctx)
+	}
+	if event.Realm == "" {
+		event.Realm = helperCtx.GetRealm(ctx)
+	}
+	if event.IP == "" {
+		event.IP = helperCtx.GetClientIP(ctx)
+	}
+	if event.RequestId == "" {
+		event.RequestId = helperCtx.GetRequestId(ctx)
+	}
+	if event.Outcome == "" {
+		event.Outcome = OutcomeSuccess
+	}
+
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() && event.TraceId == "" {
+		event.TraceId = sc.TraceID().String()
+	}
+}
+
+// Close waits for the queued events to be written and closes the sinks
+func (a *auditor) Close() error {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.queue)
+	}
+	a.mu.Unlock()
+
+	<-a.done
+
+	var errs error
+	for _, sink := range a.sinks {
+		errs = errors.Join(errs, sink.Close())
+	}
+	return errs
+}
+
+// Record appends the event to the audit trail, the actor, IP and trace id default to the values of the context.
+// It returns once the event is chained, the sinks are written in the background and their errors are logged.
+// It does nothing when audit is not configured.
+func Record(ctx context.Context, event AuditEvent) error {
+	if auditorInstance == nil {
+		return nil
+	}
+
+	err := auditorInstance.Record(ctx, event)
+	if err != nil {
+		logger.Error(ctx, err, fmt.Sprintf("Failed to record audit event %s", event.Action))
+	}
+	return err
+}
+
+func Close() error {
+	if auditorInstance == nil {
+		return nil
+	}
+	return auditorInstance.Close()
+}
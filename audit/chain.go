@@ -0,0 +1,51 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrChainBroken = errors.New("audit chain is broken")
+
+// Hash returns the SHA-256 of the event without its own hash, it covers PrevHash so each event seals the previous one
+func Hash(event AuditEvent) (string, error) {
+	event.Hash = ""
+	event.Time = event.Time.UTC()
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Verify checks the events of one chain ordered by sequence, a modified, removed or reordered event breaks it
+func Verify(events []AuditEvent) error {
+	for i, event := range events {
+		hash, err := Hash(event)
+		if err != nil {
+			return err
+		}
+		if hash != event.Hash {
+			return fmt.Errorf("%w: event %d has been modified", ErrChainBroken, event.Sequence)
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		prev := events[i-1]
+		if event.Chain != prev.Chain {
+			return fmt.Errorf("%w: event %d belongs to chain %s instead of %s", ErrChainBroken, event.Sequence, event.Chain, prev.Chain)
+		}
+		if event.Sequence != prev.Sequence+1 || event.PrevHash != prev.Hash {
+			return fmt.Errorf("%w: event %d does not follow event %d", ErrChainBroken, event.Sequence, prev.Sequence)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,302 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/alfin-efendy/helper-go/config/model"
+	"github.com/redis/go-redis/v9"
+	"gopkg.in/natefinch/lumberjack.v2"
+	"gorm.io/gorm"
+)
+
+const (
+	SinkFile  = "file"
+	SinkSql   = "sql"
+	SinkRedis = "redis"
+
+	defaultTable  = "audit_log"
+	defaultStream = "audit"
+
+	eventField     = "event"
+	headSuffix     = ":head:"
+	headFileSuffix = ".head"
+
+	// fileTailSize is the part of the audit file read to find the head of the chain
+	fileTailSize = 1 << 20
+)
+
+// Sink stores audit events, it must not modify or drop written events
+type Sink interface {
+	Write(ctx context.Context, event AuditEvent) error
+	Close() error
+}
+
+// headReader is implemented by sinks able to return the last sequence and hash of a chain
+type headReader interface {
+	Head(ctx context.Context, chain string) (uint64, string, error)
+}
+
+type fileSink struct {
+	mu     sync.Mutex
+	writer *lumberjack.Logger
+
+	// heads survive the rotation of the file, they are stored in a sidecar file next to it
+	heads    map[string]fileHead
+	headFile string
+}
+
+type fileHead struct {
+	Sequence uint64 `json:"sequence"`
+	Hash     string `json:"hash"`
+}
+
+// NewFileSink writes events as JSON lines to a dedicated file, separate from the application log
+func NewFileSink(conf *model.Audit) (Sink, error) {
+	var location string
+	if conf.Location != nil {
+		location = *conf.Location
+	} else {
+		location = filepath.Join(os.TempDir(), "logs", "audit.log")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(location), os.ModePerm); err != nil {
+		return nil, fmt.Errorf("failed to create audit directory of %s: %w", location, err)
+	}
+
+	file, err := os.OpenFile(location, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("audit location %s is not writable: %w", location, err)
+	}
+	file.Close()
+
+	sink := &fileSink{
+		writer: &lumberjack.Logger{
+			Filename:   location,
+			MaxSize:    conf.MaxSize,
+			MaxAge:     conf.MaxAge,
+			MaxBackups: conf.MaxBackups,
+			Compress:   conf.Compress,
+		},
+		heads:    make(map[string]fileHead),
+		headFile: location + headFileSuffix,
+	}
+
+	data, err := os.ReadFile(sink.headFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read audit head file %s: %w", sink.headFile, err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &sink.heads); err != nil {
+			return nil, fmt.Errorf("audit head file %s is corrupted: %w", sink.headFile, err)
+		}
+	}
+
+	return sink, nil
+}
+
+func (s *fileSink) Write(_ context.Context, event AuditEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err = s.writer.Write(append(data, '\n')); err != nil {
+		return err
+	}
+
+	s.heads[event.Chain] = fileHead{Sequence: event.Sequence, Hash: event.Hash}
+	return s.saveHeads()
+}
+
+// saveHeads replaces the sidecar file at once, a crash leaves either the previous or the new heads
+func (s *fileSink) saveHeads() error {
+	data, err := json.Marshal(s.heads)
+	if err != nil {
+		return err
+	}
+
+	tmp := s.headFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.headFile)
+}
+
+// Head returns the last event of the chain from the sidecar file or the end of the current file,
+// the file is ahead when the process stopped between writing the event and the sidecar
+func (s *fileSink) Head(_ context.Context, chain string) (uint64, string, error) {
+	s.mu.Lock()
+	head := s.heads[chain]
+	s.mu.Unlock()
+
+	sequence, hash, err := s.tail(chain)
+	if err != nil {
+		return 0, "", err
+	}
+
+	if sequence > head.Sequence {
+		return sequence, hash, nil
+	}
+	return head.Sequence, head.Hash, nil
+}
+
+// tail returns the last event of the chain found at the end of the current file
+func (s *fileSink) tail(chain string) (uint64, string, error) {
+	file, err := os.Open(s.writer.Filename)
+	if errors.Is(err, os.ErrNotExist) {
+		// Rotated away and not written since
+		return 0, "", nil
+	}
+	if err != nil {
+		return 0, "", err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, "", err
+	}
+
+	offset := info.Size() - fileTailSize
+	if offset < 0 {
+		offset = 0
+	}
+
+	tail := make([]byte, info.Size()-offset)
+	if _, err := file.ReadAt(tail, offset); err != nil && !errors.Is(err, io.EOF) {
+		return 0, "", err
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(tail), []byte("\n"))
+	for i := len(lines) - 1; i >= 0; i-- {
+		var event AuditEvent
+		if err := json.Unmarshal(lines[i], &event); err != nil {
+			// The first line of the tail may be cut
+			continue
+		}
+		if event.Chain == chain {
+			return event.Sequence, event.Hash, nil
+		}
+	}
+
+	return 0, "", nil
+}
+
+func (s *fileSink) Close() error {
+	return s.writer.Close()
+}
+
+type sqlSink struct {
+	db    *gorm.DB
+	table string
+}
+
+// NewSqlSink inserts events into the table, the table is created when it does not exist
+func NewSqlSink(ctx context.Context, db *gorm.DB, table string) (Sink, error) {
+	if table == "" {
+		table = defaultTable
+	}
+
+	if err := db.WithContext(ctx).Table(table).AutoMigrate(&AuditEvent{}); err != nil {
+		return nil, err
+	}
+
+	return &sqlSink{db: db, table: table}, nil
+}
+
+func (s *sqlSink) Write(ctx context.Context, event AuditEvent) error {
+	return s.db.WithContext(ctx).Table(s.table).Create(&event).Error
+}
+
+func (s *sqlSink) Head(ctx context.Context, chain string) (uint64, string, error) {
+	var events []AuditEvent
+	err := s.db.WithContext(ctx).
+		Table(s.table).
+		Where("chain = ?", chain).
+		Order("sequence desc").
+		Limit(1).
+		Find(&events).Error
+	if err != nil || len(events) == 0 {
+		return 0, "", err
+	}
+
+	return events[0].Sequence, events[0].Hash, nil
+}
+
+func (s *sqlSink) Close() error {
+	return nil
+}
+
+type redisSink struct {
+	client *redis.Client
+	stream string
+}
+
+// NewRedisSink appends events to the stream, the head of each chain is kept in a separate key.
+// The stream is not trimmed, dropping its oldest events would break the chains without a trace.
+func NewRedisSink(client *redis.Client, stream string) Sink {
+	if stream == "" {
+		stream = defaultStream
+	}
+
+	return &redisSink{
+		client: client,
+		stream: stream,
+	}
+}
+
+func (s *redisSink) Write(ctx context.Context, event AuditEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	args := &redis.XAddArgs{
+		Stream: s.stream,
+		Values: map[string]interface{}{
+			eventField: data,
+		},
+	}
+
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.XAdd(ctx, args)
+		pipe.Set(ctx, s.stream+headSuffix+event.Chain, fmt.Sprintf("%d:%s", event.Sequence, event.Hash), 0)
+		return nil
+	})
+	return err
+}
+
+func (s *redisSink) Head(ctx context.Context, chain string) (uint64, string, error) {
+	head, err := s.client.Get(ctx, s.stream+headSuffix+chain).Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, "", nil
+	}
+	if err != nil {
+		return 0, "", err
+	}
+
+	sequence, hash, _ := strings.Cut(head, ":")
+	value, err := strconv.ParseUint(sequence, 10, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid audit head %q of chain %s: %w", head, chain, err)
+	}
+
+	return value, hash, nil
+}
+
+func (s *redisSink) Close() error {
+	return nil
+}
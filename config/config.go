@@ -5,15 +5,23 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/alfin-efendy/helper-go/config/model"
 	"github.com/alfin-efendy/helper-go/utility"
+	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
 )
 
 var (
+	// Config is decoded once by Load and never replaced, reloaded configs are given to the OnChange listeners
 	Config *model.Config
 	raw    map[string]interface{}
+
+	viperConfig *viper.Viper
+	watchOnce   sync.Once
+	mu          sync.Mutex
+	listeners   []func(conf *model.Config)
 )
 
 func Load() {
@@ -28,6 +36,21 @@ func Load() {
 		utility.PrintPanic(fmt.Sprintf("Error reading config file: %s\n", err))
 	}
 
+	newConfig, err := decode(ViperConfig)
+	if err != nil {
+		utility.PrintPanic(fmt.Sprintf("Error reading config file: %s\n", err))
+	}
+
+	Config = newConfig
+
+	// store the raw config for later use
+	raw = ViperConfig.AllSettings()
+
+	viperConfig = ViperConfig
+}
+
+// decode resolves ${ENV} values and unmarshals the config
+func decode(ViperConfig *viper.Viper) (*model.Config, error) {
 	for _, k := range ViperConfig.AllKeys() {
 		value := ViperConfig.GetString(k)
 		if strings.HasPrefix(value, "${") && strings.HasSuffix(value, "}") {
@@ -35,14 +58,47 @@ func Load() {
 		}
 	}
 
-	Config = &model.Config{}
+	if err := checkUnitlessDurations(ViperConfig); err != nil {
+		return nil, err
+	}
 
-	if err = ViperConfig.Unmarshal(Config); err != nil {
-		utility.PrintPanic(fmt.Sprintf("Error reading config file: %s\n", err))
+	newConfig := &model.Config{}
+
+	if err := ViperConfig.Unmarshal(newConfig, viper.DecodeHook(decodeHook())); err != nil {
+		return nil, err
 	}
 
-	// store the raw config for later use
-	raw = ViperConfig.AllSettings()
+	return newConfig, nil
+}
+
+// OnChange registers fn to run with the reloaded config after the config file is changed.
+// The config file is watched from the first registration.
+func OnChange(fn func(conf *model.Config)) {
+	if viperConfig == nil {
+		return
+	}
+
+	mu.Lock()
+	listeners = append(listeners, fn)
+	mu.Unlock()
+
+	watchOnce.Do(func() {
+		viperConfig.OnConfigChange(func(_ fsnotify.Event) {
+			newConfig, err := decode(viperConfig)
+			if err != nil {
+				utility.PrintError(fmt.Sprintf("Error reloading config file: %s", err))
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			for _, listener := range listeners {
+				listener(newConfig)
+			}
+		})
+		viperConfig.WatchConfig()
+	})
 }
 
 func getVal(key string, config map[string]interface{}) interface{} {
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// durationHook decodes Go duration strings ("250ms", "5s", "1h") into time.Duration.
+// Plain numbers without a unit are read as seconds.
+func durationHook() mapstructure.DecodeHookFuncType {
+	return func(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+		if t != durationType {
+			return data, nil
+		}
+
+		switch v := data.(type) {
+		case string:
+			if v == "" {
+				return time.Duration(0), nil
+			}
+
+			if seconds, err := strconv.ParseFloat(v, 64); err == nil {
+				return time.Duration(seconds * float64(time.Second)), nil
+			}
+
+			duration, err := time.ParseDuration(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid duration %q: %w", v, err)
+			}
+			return duration, nil
+		case int:
+			return time.Duration(v) * time.Second, nil
+		case int64:
+			return time.Duration(v) * time.Second, nil
+		case float64:
+			return time.Duration(v * float64(time.Second)), nil
+		default:
+			return data, nil
+		}
+	}
+}
+
+// unitlessDurations lists the fields whose plain numbers used to have another unit than seconds,
+// they are rejected so an old config is not silently read with the new unit
+var unitlessDurations = []struct {
+	key  string
+	unit string
+}{
+	{"database.redis.slowThreshold", "milliseconds"},
+	{"database.redis.dialTimeout", "minutes"},
+	{"database.redis.readTimeout", "minutes"},
+	{"database.redis.writeTimeout", "minutes"},
+	{"database.redis.poolTimeout", "minutes"},
+	{"database.redis.minRetryBackoff", "minutes"},
+	{"database.redis.maxRetryBackoff", "minutes"},
+}
+
+// checkUnitlessDurations returns an error naming the first field of unitlessDurations set to a plain number
+func checkUnitlessDurations(ViperConfig *viper.Viper) error {
+	for _, field := range unitlessDurations {
+		if isNumber(ViperConfig.Get(field.key)) {
+			return fmt.Errorf("%s needs a unit such as \"5s\", plain numbers were %s", field.key, field.unit)
+		}
+	}
+	return nil
+}
+
+func isNumber(value interface{}) bool {
+	switch v := value.(type) {
+	case int, int64, float64:
+		return true
+	case string:
+		_, err := strconv.ParseFloat(v, 64)
+		return err == nil
+	default:
+		return false
+	}
+}
+
+// decodeHook is used to unmarshal every config model
+func decodeHook() mapstructure.DecodeHookFunc {
+	return mapstructure.ComposeDecodeHookFunc(
+		durationHook(),
+		mapstructure.StringToSliceHookFunc(","),
+	)
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alfin-efendy/helper-go/config/model"
+	"github.com/spf13/viper"
+)
+
+func decodeYaml(t *testing.T, yaml string) (*model.Config, error) {
+	t.Helper()
+
+	ViperConfig := viper.New()
+	ViperConfig.SetConfigType("yaml")
+	if err := ViperConfig.ReadConfig(strings.NewReader(yaml)); err != nil {
+		t.Fatalf("failed to read config: %s", err)
+	}
+
+	return decode(ViperConfig)
+}
+
+func TestDecodeDurations(t *testing.T) {
+	conf, err := decodeYaml(t, `
+database:
+  redis:
+    address: localhost:6379
+    db: 2
+    readTimeout: 250ms
+    writeTimeout: 5s
+    slowThreshold: 100ms
+otel:
+  timeout: 3
+`)
+	if err != nil {
+		t.Fatalf("failed to decode config: %s", err)
+	}
+
+	redis := conf.Database.Redis
+	if redis.Address != "localhost:6379" {
+		t.Errorf("address = %q, want localhost:6379", redis.Address)
+	}
+	if redis.DB == nil || *redis.DB != 2 {
+		t.Errorf("db = %v, want 2", redis.DB)
+	}
+	if redis.ReadTimeout == nil || *redis.ReadTimeout != 250*time.Millisecond {
+		t.Errorf("readTimeout = %v, want 250ms", redis.ReadTimeout)
+	}
+	if redis.WriteTimeout == nil || *redis.WriteTimeout != 5*time.Second {
+		t.Errorf("writeTimeout = %v, want 5s", redis.WriteTimeout)
+	}
+	if redis.SlowThreshold == nil || *redis.SlowThreshold != 100*time.Millisecond {
+		t.Errorf("slowThreshold = %v, want 100ms", redis.SlowThreshold)
+	}
+
+	// Plain numbers are seconds for the fields that were already in seconds
+	if conf.Otel.Timeout != 3*time.Second {
+		t.Errorf("otel timeout = %v, want 3s", conf.Otel.Timeout)
+	}
+}
+
+func TestDecodeRejectsUnitlessRedisDurations(t *testing.T) {
+	for _, yaml := range []string{
+		"database:\n  redis:\n    readTimeout: 1\n",
+		"database:\n  redis:\n    poolTimeout: \"1\"\n",
+		"database:\n  redis:\n    slowThreshold: 250\n",
+	} {
+		if _, err := decodeYaml(t, yaml); err == nil {
+			t.Errorf("decoding %q should fail", yaml)
+		}
+	}
+}
@@ -4,4 +4,6 @@ type app struct {
 	Name   string `mapstructure:"name"`
 	Mode   string `mapstructure:"mode"`
 	Domain string `mapstructure:"domain"`
+	// Version defaults to the module version or VCS revision of the build
+	Version string `mapstructure:"version"`
 }
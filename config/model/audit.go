@@ -0,0 +1,18 @@
+package model
+
+type Audit struct {
+	// Chain names the hash chain of this instance, defaults to the host name
+	Chain string `mapstructure:"chain"`
+	// Sinks are file, sql or redis, the file sink is used when empty
+	Sinks []string `mapstructure:"sinks"`
+	// Location is the file of the file sink
+	Location   *string `mapstructure:"location"`
+	MaxSize    int     `mapstructure:"maxSize"`
+	MaxAge     int     `mapstructure:"maxAge"`
+	MaxBackups int     `mapstructure:"maxBackups"`
+	Compress   bool    `mapstructure:"compress"`
+	// Table is the table of the sql sink
+	Table string `mapstructure:"table"`
+	// Stream is the stream of the redis sink, it is never trimmed so Verify can rebuild every chain from it
+	Stream string `mapstructure:"stream"`
+}
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type database struct {
 	Sql   *sql   `mapstructure:"sql"`
 	Redis *redis `mapstructure:"redis"`
@@ -12,40 +14,48 @@ type sql struct {
 	Username          string             `mapstructure:"username"`
 	Password          string             `mapstructure:"password"`
 	PoolingConnection *poolingConnection `mapstructure:"poolingConnection"`
+	// SlowThreshold statements slower than this are logged
+	SlowThreshold *time.Duration `mapstructure:"slowThreshold"`
+	// LogLevel is silent, error, warn or info, defaults to follow the log level
+	LogLevel string `mapstructure:"logLevel"`
 }
 
 type poolingConnection struct {
-	MaxIdle     int   `mapstructure:"maxIdle"`
-	MaxOpen     int   `mapstructure:"maxOpen"`
-	MaxLifetime int64 `mapstructure:"maxLifetime"`
+	MaxIdle     int           `mapstructure:"maxIdle"`
+	MaxOpen     int           `mapstructure:"maxOpen"`
+	MaxLifetime time.Duration `mapstructure:"maxLifetime"`
 }
 
 type redis struct {
 	Mode string `mapstructure:"mode"`
-	redisCluster
+	// SlowThreshold commands slower than this are logged, it needs a unit ("250ms"), plain numbers were milliseconds and are rejected
+	SlowThreshold *time.Duration `mapstructure:"slowThreshold"`
+	redisCluster  `mapstructure:",squash"`
 }
 
 type redisSingle struct {
-	Address         string  `mapstructure:"address"`
-	Username        *string `mapstructure:"username"`
-	Password        *string `mapstructure:"password"`
-	DB              *int    `mapstructure:"db"`
-	Network         *string `mapstructure:"network"`
-	MaxRetries      *int    `mapstructure:"maxRetries"`
-	MaxRetryBackoff *int    `mapstructure:"maxRetryBackoff"`
-	MinRetryBackoff *int    `mapstructure:"minRetryBackoff"`
-	DialTimeout     *int    `mapstructure:"dialTimeout"`
-	ReadTimeout     *int    `mapstructure:"readTimeout"`
-	WriteTimeout    *int    `mapstructure:"writeTimeout"`
-	PoolFIFO        *bool   `mapstructure:"poolFIFO"`
-	PoolSize        *int    `mapstructure:"poolSize"`
-	PoolTimeout     *int    `mapstructure:"poolTimeout"`
-	MinIdleConns    *int    `mapstructure:"minIdleConns"`
-	MaxIdleConns    *int    `mapstructure:"maxIdleConns"`
+	Address    string  `mapstructure:"address"`
+	Username   *string `mapstructure:"username"`
+	Password   *string `mapstructure:"password"`
+	DB         *int    `mapstructure:"db"`
+	Network    *string `mapstructure:"network"`
+	MaxRetries *int    `mapstructure:"maxRetries"`
+	// The durations below need a unit ("250ms", "5s"), plain numbers were minutes and are rejected
+	MaxRetryBackoff *time.Duration `mapstructure:"maxRetryBackoff"`
+	MinRetryBackoff *time.Duration `mapstructure:"minRetryBackoff"`
+	DialTimeout     *time.Duration `mapstructure:"dialTimeout"`
+	ReadTimeout     *time.Duration `mapstructure:"readTimeout"`
+	WriteTimeout    *time.Duration `mapstructure:"writeTimeout"`
+	// PoolTimeout needs a unit ("5s"), plain numbers were minutes and are rejected
+	PoolTimeout  *time.Duration `mapstructure:"poolTimeout"`
+	PoolFIFO     *bool          `mapstructure:"poolFIFO"`
+	PoolSize     *int           `mapstructure:"poolSize"`
+	MinIdleConns *int           `mapstructure:"minIdleConns"`
+	MaxIdleConns *int           `mapstructure:"maxIdleConns"`
 }
 
 type redisCluster struct {
-	redisSingle
+	redisSingle             `mapstructure:",squash"`
 	SentinelAddress         []string `mapstructure:"sentinelAddress"`
 	MasterName              string   `mapstructure:"masterName"`
 	RouteByLatency          *bool    `mapstructure:"routeByLatency"`
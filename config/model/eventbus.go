@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+type eventBus struct {
+	Consumer     string        `mapstructure:"consumer"`
+	MaxRetries   int           `mapstructure:"maxRetries"`
+	BatchSize    int64         `mapstructure:"batchSize"`
+	MaxLen       int64         `mapstructure:"maxLen"`
+	BlockTimeout time.Duration `mapstructure:"blockTimeout"`
+	ClaimIdle    time.Duration `mapstructure:"claimIdle"`
+	// TrustIdentityBaggage reads the user.id, user.full_name and user.realm baggage of the events,
+	// only enable it when every publisher of the stream is trusted to set them
+	TrustIdentityBaggage bool `mapstructure:"trustIdentityBaggage"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+type httpClient struct {
+	// Timeout limits a whole call including retries
+	Timeout               time.Duration `mapstructure:"timeout"`
+	DialTimeout           time.Duration `mapstructure:"dialTimeout"`
+	TLSHandshakeTimeout   time.Duration `mapstructure:"tlsHandshakeTimeout"`
+	ResponseHeaderTimeout time.Duration `mapstructure:"responseHeaderTimeout"`
+	IdleConnTimeout       time.Duration `mapstructure:"idleConnTimeout"`
+	MaxIdleConns          int           `mapstructure:"maxIdleConns"`
+	MaxIdleConnsPerHost   int           `mapstructure:"maxIdleConnsPerHost"`
+
+	Retry          *httpClientRetry `mapstructure:"retry"`
+	CircuitBreaker *circuitBreaker  `mapstructure:"circuitBreaker"`
+}
+
+type httpClientRetry struct {
+	// MaxAttempts includes the first call, 1 disables retries
+	MaxAttempts    int           `mapstructure:"maxAttempts"`
+	InitialBackoff time.Duration `mapstructure:"initialBackoff"`
+	MaxBackoff     time.Duration `mapstructure:"maxBackoff"`
+}
+
+type circuitBreaker struct {
+	// FailureThreshold consecutive failures of a host open its circuit
+	FailureThreshold int `mapstructure:"failureThreshold"`
+	// OpenTimeout is how long calls to the host are rejected before a trial call is let through
+	OpenTimeout time.Duration `mapstructure:"openTimeout"`
+}
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type log struct {
 	Level      string  `mapstructure:"level"`
 	Location   *string `mapstructure:"location"`
@@ -8,4 +10,75 @@ type log struct {
 	MaxBackups int     `mapstructure:"maxBackups"`
 	TimeZone   string  `mapstructure:"timeZone"`
 	Compress   bool    `mapstructure:"compress"`
+	// TimeFormat is rfc3339, rfc3339nano, iso8601, epoch, epochMillis, epochNanos or a Go time layout,
+	// JSON entries default to epoch, or rfc3339nano with the ecs, gcp and datadog field keys
+	TimeFormat string `mapstructure:"timeFormat"`
+	// FieldKeys is the key convention of JSON entries: default, ecs, gcp or datadog
+	FieldKeys string   `mapstructure:"fieldKeys"`
+	Keys      *logKeys `mapstructure:"keys"`
+
+	Sinks     []LogSink    `mapstructure:"sinks"`
+	Redaction *redaction   `mapstructure:"redaction"`
+	Sampling  *logSampling `mapstructure:"sampling"`
+	Dedup     *logDedup    `mapstructure:"dedup"`
+}
+
+type LogSink struct {
+	// Type is one of stdout, stderr, file or none
+	Type string `mapstructure:"type"`
+	// Level defaults to the log level
+	Level string `mapstructure:"level"`
+	// Encoding is json or console, console is colored when written to a terminal stream
+	Encoding string `mapstructure:"encoding"`
+}
+
+// logKeys overrides single keys of the field key convention
+type logKeys struct {
+	Time       string `mapstructure:"time"`
+	Level      string `mapstructure:"level"`
+	Message    string `mapstructure:"message"`
+	Name       string `mapstructure:"name"`
+	Stacktrace string `mapstructure:"stacktrace"`
+}
+
+type redaction struct {
+	// Fields are JSON or form field names masked at any depth, or dotted paths like user.password
+	Fields []string `mapstructure:"fields"`
+	// Headers are request header names masked in logs
+	Headers []string `mapstructure:"headers"`
+	// Patterns are regex rules applied to logged bodies, "email" and "card" are built in
+	Patterns []redactionPattern `mapstructure:"patterns"`
+	// MaxBodySize truncates logged bodies, in bytes
+	MaxBodySize int `mapstructure:"maxBodySize"`
+	// SkipContentTypes are content type prefixes whose bodies are not logged
+	SkipContentTypes []string `mapstructure:"skipContentTypes"`
+	// SqlMode is full to log SQL with bound values or parameterized to log placeholders only
+	SqlMode string `mapstructure:"sqlMode"`
+	Mask    string `mapstructure:"mask"`
+}
+
+type redactionPattern struct {
+	Name        string `mapstructure:"name"`
+	Regex       string `mapstructure:"regex"`
+	Replacement string `mapstructure:"replacement"`
+}
+
+type logSampling struct {
+	Tick time.Duration `mapstructure:"tick"`
+	// First and Thereafter apply to every level without its own rule
+	First      int                     `mapstructure:"first"`
+	Thereafter int                     `mapstructure:"thereafter"`
+	Levels     map[string]samplingRule `mapstructure:"levels"`
+}
+
+type samplingRule struct {
+	// First entries with the same level and message are logged per tick
+	First int `mapstructure:"first"`
+	// Thereafter every Mth entry is logged for the rest of the tick
+	Thereafter int `mapstructure:"thereafter"`
+}
+
+type logDedup struct {
+	// Window repeated error messages are collapsed into one summary entry
+	Window time.Duration `mapstructure:"window"`
 }
@@ -1,8 +1,63 @@
 package model
 
+import "time"
+
 type Otel struct {
-	Host    string `mapstructure:"host"`
-	Timeout int    `mapstructure:"timeout"`
-	Trace   bool   `mapstructure:"trace"`
-	Metric  bool   `mapstructure:"metric"`
+	Host    string        `mapstructure:"host"`
+	Timeout time.Duration `mapstructure:"timeout"`
+	Trace   bool          `mapstructure:"trace"`
+	Metric  bool          `mapstructure:"metric"`
+	Log     bool          `mapstructure:"log"`
+	// Runtime collects goroutine, memory, GC, CPU and file descriptor metrics when metric is enabled
+	Runtime bool `mapstructure:"runtime"`
+	// Exporter is otlp or stdout, stdout prints telemetry for local debugging
+	Exporter string `mapstructure:"exporter"`
+	// Protocol of the otlp exporter is grpc or http
+	Protocol string `mapstructure:"protocol"`
+	// Headers are sent with every export, like the API key of the collector
+	Headers map[string]string `mapstructure:"headers"`
+	// TLS secures the collector connection, it is insecure when not set
+	TLS            *OtelTLS      `mapstructure:"tls"`
+	Sampler        *otelSampler  `mapstructure:"sampler"`
+	MetricInterval time.Duration `mapstructure:"metricInterval"`
+	Batch          *otelBatch    `mapstructure:"batch"`
+	// Prometheus exposes the metrics to be scraped, metrics are only pushed when a host is set
+	Prometheus *otelPrometheus `mapstructure:"prometheus"`
+	Resource   *otelResource   `mapstructure:"resource"`
+}
+
+type OtelTLS struct {
+	CAFile             string `mapstructure:"caFile"`
+	CertFile           string `mapstructure:"certFile"`
+	KeyFile            string `mapstructure:"keyFile"`
+	ServerName         string `mapstructure:"serverName"`
+	InsecureSkipVerify bool   `mapstructure:"insecureSkipVerify"`
+}
+
+type otelSampler struct {
+	// Ratio of the traces sampled, between 0 and 1
+	Ratio float64 `mapstructure:"ratio"`
+	// ParentBased follows the sampling decision of the parent span, defaults to true
+	ParentBased *bool `mapstructure:"parentBased"`
+}
+
+type otelBatch struct {
+	MaxQueueSize       int           `mapstructure:"maxQueueSize"`
+	MaxExportBatchSize int           `mapstructure:"maxExportBatchSize"`
+	BatchTimeout       time.Duration `mapstructure:"batchTimeout"`
+	ExportTimeout      time.Duration `mapstructure:"exportTimeout"`
+}
+
+type otelPrometheus struct {
+	// Path defaults to /metrics
+	Path string `mapstructure:"path"`
+	// Port serves the path on its own server, the path is added to the REST API server when it is 0
+	Port int `mapstructure:"port"`
+}
+
+type otelResource struct {
+	// InstanceId defaults to the pod name, then a random id per process
+	InstanceId string `mapstructure:"instanceId"`
+	// Attributes are added to every span, metric and log, nested keys are joined with a dot
+	Attributes map[string]interface{} `mapstructure:"attributes"`
 }
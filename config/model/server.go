@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type server struct {
 	RestAPI *restAPI `mapstructure:"restAPI"`
 }
@@ -9,6 +11,16 @@ type restAPI struct {
 	Port   int    `mapstructure:"port"`
 	Stdout bool   `mapstructure:"stdout"`
 	Cors   *cors  `mapstructure:"cors"`
+
+	Idempotency *idempotency `mapstructure:"idempotency"`
+	LogLevel    *logLevel    `mapstructure:"logLevel"`
+	// LogSkipPaths are routes not logged by the request logger, e.g. health checks
+	LogSkipPaths []string `mapstructure:"logSkipPaths"`
+	// Metrics records request rate, errors and latency per route, defaults to true when otel.metric is on
+	Metrics *bool `mapstructure:"metrics"`
+	// TrustIdentityHeaders reads X-User-Id, X-Full-Name, X-Realm and the identity baggage on every route,
+	// only enable it when a gateway strips these headers from external requests
+	TrustIdentityHeaders bool `mapstructure:"trustIdentityHeaders"`
 }
 
 type cors struct {
@@ -19,3 +31,16 @@ type cors struct {
 	ExposeHeaders    []string `mapstructure:"exposeHeaders"`
 	MaxAge           int      `mapstructure:"maxAge"`
 }
+
+type idempotency struct {
+	// TTL is how long a completed response is kept for replay
+	TTL time.Duration `mapstructure:"ttl"`
+	// LockTimeout is how long a request is held as in-flight
+	LockTimeout time.Duration `mapstructure:"lockTimeout"`
+}
+
+type logLevel struct {
+	Path string `mapstructure:"path"`
+	// Permission is required, it is the token audience allowed to read and change the level
+	Permission string `mapstructure:"permission"`
+}
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+type session struct {
+	Name          string        `mapstructure:"name"`
+	Store         string        `mapstructure:"store"`
+	Secret        string        `mapstructure:"secret"`
+	EncryptionKey string        `mapstructure:"encryptionKey"`
+	TTL           time.Duration `mapstructure:"ttl"`
+	Path          string        `mapstructure:"path"`
+	Secure        *bool         `mapstructure:"secure"`
+	SameSite      string        `mapstructure:"sameSite"`
+}
@@ -1,10 +1,16 @@
 package model
 
+import "time"
+
 type token struct {
-	AccessPrivateKey  string `mapstructure:"accessPrivateKey"`
-	AccessPublicKey   string `mapstructure:"accessPublicKey"`
-	AccessExpireHour  int    `mapstructure:"accessExpireHour"`
-	RefreshPrivateKey string `mapstructure:"refreshPrivateKey"`
-	RefreshPublicKey  string `mapstructure:"refreshPublicKey"`
-	RefreshExpireHour int    `mapstructure:"refreshExpireHour"`
+	AccessPrivateKey  string        `mapstructure:"accessPrivateKey"`
+	AccessPublicKey   string        `mapstructure:"accessPublicKey"`
+	AccessExpire      time.Duration `mapstructure:"accessExpire"`
+	RefreshPrivateKey string        `mapstructure:"refreshPrivateKey"`
+	RefreshPublicKey  string        `mapstructure:"refreshPublicKey"`
+	RefreshExpire     time.Duration `mapstructure:"refreshExpire"`
+
+	// Deprecated: use AccessExpire and RefreshExpire instead
+	AccessExpireHour  int `mapstructure:"accessExpireHour"`
+	RefreshExpireHour int `mapstructure:"refreshExpireHour"`
 }
@@ -2,43 +2,132 @@ package context
 
 import (
 	"context"
+
+	"go.opentelemetry.io/otel/baggage"
 )
 
 const (
-	userIdKey   = "userId"
-	fullNameKey = "fullName"
-	realmKey    = "realm"
+	userIdKey    = "userId"
+	fullNameKey  = "fullName"
+	realmKey     = "realm"
+	requestIdKey = "requestId"
+	routeKey     = "route"
+	clientIPKey  = "clientIP"
+	subjectKey   = "subject"
+
+	// Baggage members carry the identity to the services called downstream
+	UserIdBaggageKey   = "user.id"
+	FullNameBaggageKey = "user.full_name"
+	RealmBaggageKey    = "user.realm"
 )
 
 func SetUserId(ctx context.Context, userId string) context.Context {
+	ctx = setBaggage(ctx, UserIdBaggageKey, userId)
 	return context.WithValue(ctx, userIdKey, userId)
 }
 
 func GetUserId(ctx context.Context) string {
-	if userId, ok := ctx.Value(userIdKey).(string); ok {
+	if userId, ok := ctx.Value(userIdKey).(string); ok && userId != "" {
 		return userId
 	}
-	return ""
+	return baggage.FromContext(ctx).Member(UserIdBaggageKey).Value()
 }
 
 func SetFullName(ctx context.Context, fullName string) context.Context {
+	ctx = setBaggage(ctx, FullNameBaggageKey, fullName)
 	return context.WithValue(ctx, fullNameKey, fullName)
 }
 
 func GetFullName(ctx context.Context) string {
-	if fullName, ok := ctx.Value(fullNameKey).(string); ok {
+	if fullName, ok := ctx.Value(fullNameKey).(string); ok && fullName != "" {
 		return fullName
 	}
-	return ""
+	return baggage.FromContext(ctx).Member(FullNameBaggageKey).Value()
 }
 
 func SetRealm(ctx context.Context, realm string) context.Context {
+	ctx = setBaggage(ctx, RealmBaggageKey, realm)
 	return context.WithValue(ctx, realmKey, realm)
 }
 
 func GetRealm(ctx context.Context) string {
-	if realm, ok := ctx.Value(realmKey).(string); ok {
+	if realm, ok := ctx.Value(realmKey).(string); ok && realm != "" {
 		return realm
 	}
+	return baggage.FromContext(ctx).Member(RealmBaggageKey).Value()
+}
+
+func SetRequestId(ctx context.Context, requestId string) context.Context {
+	return context.WithValue(ctx, requestIdKey, requestId)
+}
+
+func GetRequestId(ctx context.Context) string {
+	if requestId, ok := ctx.Value(requestIdKey).(string); ok {
+		return requestId
+	}
 	return ""
 }
+
+func SetRoute(ctx context.Context, route string) context.Context {
+	return context.WithValue(ctx, routeKey, route)
+}
+
+func GetRoute(ctx context.Context) string {
+	if route, ok := ctx.Value(routeKey).(string); ok {
+		return route
+	}
+	return ""
+}
+
+func SetClientIP(ctx context.Context, clientIP string) context.Context {
+	return context.WithValue(ctx, clientIPKey, clientIP)
+}
+
+func GetClientIP(ctx context.Context) string {
+	if clientIP, ok := ctx.Value(clientIPKey).(string); ok {
+		return clientIP
+	}
+	return ""
+}
+
+// SetSubject stores the subject of the verified access token, unlike the user id it is never forwarded by callers
+func SetSubject(ctx context.Context, subject string) context.Context {
+	return context.WithValue(ctx, subjectKey, subject)
+}
+
+func GetSubject(ctx context.Context) string {
+	if subject, ok := ctx.Value(subjectKey).(string); ok {
+		return subject
+	}
+	return ""
+}
+
+// setBaggage writes the member to the W3C baggage of the context, an empty value removes it
+// DropIdentityBaggage removes the identity members from the baggage of a caller that is not trusted to forward them
+func DropIdentityBaggage(ctx context.Context) context.Context {
+	bag := baggage.FromContext(ctx).
+		DeleteMember(UserIdBaggageKey).
+		DeleteMember(FullNameBaggageKey).
+		DeleteMember(RealmBaggageKey)
+	return baggage.ContextWithBaggage(ctx, bag)
+}
+
+func setBaggage(ctx context.Context, key string, value string) context.Context {
+	bag := baggage.FromContext(ctx)
+	if value == "" {
+		return baggage.ContextWithBaggage(ctx, bag.DeleteMember(key))
+	}
+
+	member, err := baggage.NewMemberRaw(key, value)
+	if err != nil {
+		return ctx
+	}
+
+	// The baggage is left unchanged when it would exceed the W3C limits
+	bag, err = bag.SetMember(member)
+	if err != nil {
+		return ctx
+	}
+
+	return baggage.ContextWithBaggage(ctx, bag)
+}
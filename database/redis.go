@@ -3,7 +3,6 @@ package database
 import (
 	"context"
 	"fmt"
-	"time"
 
 	"github.com/alfin-efendy/helper-go/config"
 	"github.com/alfin-efendy/helper-go/config/model"
@@ -30,6 +29,24 @@ func initRedis(ctx context.Context) {
 		return
 	}
 
+	db := 0
+	if config.Database.Redis.DB != nil {
+		db = *config.Database.Redis.DB
+	}
+
+	slowThreshold := defaultRedisSlowThreshold
+	if config.Database.Redis.SlowThreshold != nil {
+		slowThreshold = *config.Database.Redis.SlowThreshold
+	}
+
+	// Instrument every command with a span, a duration metric and a slow log
+	hook, err := newRedisHook(config.App.Name, db, slowThreshold)
+	if err != nil {
+		logger.Fatal(ctx, err, "❌ Failed to instrument redis client")
+		return
+	}
+	redisClient.AddHook(hook)
+
 	logger.Info(ctx, "✅ Redis client connected")
 }
 
@@ -49,19 +66,19 @@ func initSingleMode(ctx context.Context, config *model.Config) *redis.Client {
 		option.DB = *configRedis.DB
 	}
 	if configRedis.MinRetryBackoff != nil {
-		option.MinRetryBackoff = time.Duration(*configRedis.MinRetryBackoff) * time.Minute
+		option.MinRetryBackoff = *configRedis.MinRetryBackoff
 	}
 	if configRedis.MaxRetryBackoff != nil {
-		option.MaxRetryBackoff = time.Duration(*configRedis.MaxRetryBackoff) * time.Minute
+		option.MaxRetryBackoff = *configRedis.MaxRetryBackoff
 	}
 	if configRedis.DialTimeout != nil {
-		option.DialTimeout = time.Duration(*configRedis.DialTimeout) * time.Minute
+		option.DialTimeout = *configRedis.DialTimeout
 	}
 	if configRedis.ReadTimeout != nil {
-		option.ReadTimeout = time.Duration(*configRedis.ReadTimeout) * time.Minute
+		option.ReadTimeout = *configRedis.ReadTimeout
 	}
 	if configRedis.WriteTimeout != nil {
-		option.WriteTimeout = time.Duration(*configRedis.WriteTimeout) * time.Minute
+		option.WriteTimeout = *configRedis.WriteTimeout
 	}
 	if configRedis.PoolFIFO != nil {
 		option.PoolFIFO = *configRedis.PoolFIFO
@@ -70,7 +87,7 @@ func initSingleMode(ctx context.Context, config *model.Config) *redis.Client {
 		option.PoolSize = *configRedis.PoolSize
 	}
 	if configRedis.PoolTimeout != nil {
-		option.PoolTimeout = time.Duration(*configRedis.PoolTimeout) * time.Minute
+		option.PoolTimeout = *configRedis.PoolTimeout
 	}
 	if configRedis.MinIdleConns != nil {
 		option.MinIdleConns = *configRedis.MinIdleConns
@@ -104,19 +121,19 @@ func initSentinelMode(ctx context.Context, config *model.Config) *redis.Client {
 		option.DB = *configRedis.DB
 	}
 	if configRedis.MinRetryBackoff != nil {
-		option.MinRetryBackoff = time.Duration(*configRedis.MinRetryBackoff) * time.Minute
+		option.MinRetryBackoff = *configRedis.MinRetryBackoff
 	}
 	if configRedis.MaxRetryBackoff != nil {
-		option.MaxRetryBackoff = time.Duration(*configRedis.MaxRetryBackoff) * time.Minute
+		option.MaxRetryBackoff = *configRedis.MaxRetryBackoff
 	}
 	if configRedis.DialTimeout != nil {
-		option.DialTimeout = time.Duration(*configRedis.DialTimeout) * time.Minute
+		option.DialTimeout = *configRedis.DialTimeout
 	}
 	if configRedis.ReadTimeout != nil {
-		option.ReadTimeout = time.Duration(*configRedis.ReadTimeout) * time.Minute
+		option.ReadTimeout = *configRedis.ReadTimeout
 	}
 	if configRedis.WriteTimeout != nil {
-		option.WriteTimeout = time.Duration(*configRedis.WriteTimeout) * time.Minute
+		option.WriteTimeout = *configRedis.WriteTimeout
 	}
 	if configRedis.PoolFIFO != nil {
 		option.PoolFIFO = *configRedis.PoolFIFO
@@ -125,7 +142,7 @@ func initSentinelMode(ctx context.Context, config *model.Config) *redis.Client {
 		option.PoolSize = *configRedis.PoolSize
 	}
 	if configRedis.PoolTimeout != nil {
-		option.PoolTimeout = time.Duration(*configRedis.PoolTimeout) * time.Minute
+		option.PoolTimeout = *configRedis.PoolTimeout
 	}
 	if configRedis.MinIdleConns != nil {
 		option.MinIdleConns = *configRedis.MinIdleConns
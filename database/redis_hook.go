@@ -0,0 +1,196 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alfin-efendy/helper-go/logger"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	redisKeyCountKey      = attribute.Key("db.redis.key_count")
+	redisPipelineCountKey = attribute.Key("db.redis.pipeline_length")
+	redisBlockingKey      = attribute.Key("db.redis.blocking")
+
+	defaultRedisSlowThreshold = 200 * time.Millisecond
+)
+
+// redisHook traces, measures and logs slow redis commands
+type redisHook struct {
+	tracer        trace.Tracer
+	duration      metric.Float64Histogram
+	attrs         []attribute.KeyValue
+	slowThreshold time.Duration
+
+	// operations caches the metric attributes of each operation name, command names are a small fixed set
+	operations sync.Map
+}
+
+func newRedisHook(name string, db int, slowThreshold time.Duration) (*redisHook, error) {
+	duration, err := newOperationDuration(otel.Meter(name))
+	if err != nil {
+		return nil, err
+	}
+
+	return &redisHook{
+		tracer:   otel.Tracer(name),
+		duration: duration,
+		attrs: []attribute.KeyValue{
+			semconv.DBSystemRedis,
+			semconv.DBNamespace(strconv.Itoa(db)),
+		},
+		slowThreshold: slowThreshold,
+	}, nil
+}
+
+func (h *redisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h *redisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		keys := redisKeyCount(cmd)
+		blocking := redisBlocking(cmd)
+
+		start := time.Now()
+		ctx, span := h.tracer.Start(ctx, "redis."+cmd.FullName(),
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(h.attrs...),
+			trace.WithAttributes(
+				semconv.DBOperationName(cmd.FullName()),
+				redisKeyCountKey.Int(keys),
+				redisBlockingKey.Bool(blocking),
+			),
+		)
+
+		err := next(ctx, cmd)
+		h.end(ctx, span, start, cmd.FullName(), keys, blocking, err)
+
+		return err
+	}
+}
+
+func (h *redisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		keys := 0
+		blocking := false
+		names := make([]string, 0, len(cmds))
+		for _, cmd := range cmds {
+			keys += redisKeyCount(cmd)
+			blocking = blocking || redisBlocking(cmd)
+			names = append(names, cmd.FullName())
+		}
+
+		start := time.Now()
+		ctx, span := h.tracer.Start(ctx, "redis.pipeline",
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(h.attrs...),
+			trace.WithAttributes(
+				semconv.DBOperationName("pipeline"),
+				semconv.DBQueryText(strings.Join(names, " ")),
+				redisKeyCountKey.Int(keys),
+				redisPipelineCountKey.Int(len(cmds)),
+				redisBlockingKey.Bool(blocking),
+			),
+		)
+
+		err := next(ctx, cmds)
+		if err == nil {
+			for _, cmd := range cmds {
+				if cmdErr := cmd.Err(); cmdErr != nil && !errors.Is(cmdErr, redis.Nil) {
+					err = cmdErr
+					break
+				}
+			}
+		}
+
+		h.end(ctx, span, start, "pipeline", keys, blocking, err)
+
+		return err
+	}
+}
+
+// end records the span status, the duration metric and the slow command log.
+// Blocking commands wait for data by design, they are left out of the metric and the slow log.
+func (h *redisHook) end(ctx context.Context, span trace.Span, start time.Time, operation string, keys int, blocking bool, err error) {
+	elapsed := time.Since(start)
+
+	// A missing key is a normal result and not an error
+	if err != nil && !errors.Is(err, redis.Nil) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+
+	if blocking {
+		return
+	}
+
+	h.duration.Record(ctx, elapsed.Seconds(), h.operationAttributes(operation))
+
+	if h.slowThreshold > 0 && elapsed > h.slowThreshold {
+		logger.Warn(ctx, "Slow redis command",
+			zap.String("operation", operation),
+			zap.Int("keys", keys),
+			zap.Duration("latency", elapsed),
+			zap.Duration("threshold", h.slowThreshold),
+		)
+	}
+}
+
+// operationAttributes returns the metric attributes of the operation, built once per operation name
+func (h *redisHook) operationAttributes(operation string) metric.MeasurementOption {
+	if option, ok := h.operations.Load(operation); ok {
+		return option.(metric.MeasurementOption)
+	}
+
+	attrs := append([]attribute.KeyValue{semconv.DBOperationName(operation)}, h.attrs...)
+	option, _ := h.operations.LoadOrStore(operation, metric.WithAttributeSet(attribute.NewSet(attrs...)))
+	return option.(metric.MeasurementOption)
+}
+
+// redisKeyCount estimates the number of keys a command touches from its arguments
+func redisKeyCount(cmd redis.Cmder) int {
+	args := len(cmd.Args()) - 1
+	if args <= 0 {
+		return 0
+	}
+
+	switch cmd.Name() {
+	case "mget", "del", "unlink", "exists", "touch", "watch":
+		return args
+	case "mset", "msetnx":
+		return args / 2
+	case "ping", "info", "select", "auth", "hello", "client", "cluster", "config", "dbsize", "flushdb", "flushall", "time":
+		return 0
+	default:
+		return 1
+	}
+}
+
+// redisBlocking reports whether the command waits on the server for data, like the stream consumer poll
+func redisBlocking(cmd redis.Cmder) bool {
+	switch cmd.Name() {
+	case "blpop", "brpop", "brpoplpush", "blmove", "blmpop", "bzpopmin", "bzpopmax", "bzmpop", "wait", "waitaof":
+		return true
+	case "xread", "xreadgroup":
+		for _, arg := range cmd.Args() {
+			if name, ok := arg.(string); ok && strings.EqualFold(name, "block") {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"strings"
-	"time"
 
 	"github.com/alfin-efendy/helper-go/config"
 	log "github.com/alfin-efendy/helper-go/logger"
@@ -25,9 +24,16 @@ var (
 		zapcore.InfoLevel:  logger.Warn,
 		zapcore.DebugLevel: logger.Info,
 	}
+	gormLevelMap = map[string]logger.LogLevel{
+		"silent": logger.Silent,
+		"error":  logger.Error,
+		"warn":   logger.Warn,
+		"info":   logger.Info,
+	}
 )
 
 func initSql(ctx context.Context) {
+	appName := config.Config.App.Name
 	config := config.Config.Database.Sql
 	if config == nil {
 		log.Warn(ctx, "❌ Database configuration is not found")
@@ -47,11 +53,27 @@ func initSql(ctx context.Context) {
 		),
 	)
 
+	gormLogLevel := zapLevelMap[logLevel]
+	if config.LogLevel != "" {
+		level, ok := gormLevelMap[strings.ToLower(config.LogLevel)]
+		if !ok {
+			log.Fatal(ctx, fmt.Errorf("sql log level %s is not supported", config.LogLevel), "❌ Failed unsupported sql log level")
+			return
+		}
+		gormLogLevel = level
+	}
+
+	slowThreshold := defaultSqlSlowThreshold
+	if config.SlowThreshold != nil {
+		slowThreshold = *config.SlowThreshold
+	}
+
 	loggerConfig := logger.Config{
-		SlowThreshold:             3 * time.Second,
-		LogLevel:                  zapLevelMap[logLevel],
+		SlowThreshold:             slowThreshold,
+		LogLevel:                  gormLogLevel,
 		Colorful:                  true,
 		IgnoreRecordNotFoundError: true,
+		ParameterizedQueries:      log.GetRedactor().ParameterizedSql(),
 	}
 
 	logging := log.New(log.WithConfig(loggerConfig))
@@ -71,6 +93,17 @@ func initSql(ctx context.Context) {
 		return
 	}
 
+	// Trace and measure every statement
+	plugin, err := newSqlPlugin(appName, config.Database, config.Username)
+	if err != nil {
+		log.Fatal(ctx, err, "❌ Failed to instrument sql client")
+		return
+	}
+	if err := db.Use(plugin); err != nil {
+		log.Fatal(ctx, err, "❌ Failed to instrument sql client")
+		return
+	}
+
 	log.Info(ctx, "✅ Database connection established")
 
 	db.Session(&gorm.Session{
@@ -86,7 +119,7 @@ func initSql(ctx context.Context) {
 
 	dbSql.SetMaxIdleConns(config.PoolingConnection.MaxIdle)
 	dbSql.SetMaxOpenConns(config.PoolingConnection.MaxOpen)
-	dbSql.SetConnMaxLifetime(time.Duration(config.PoolingConnection.MaxLifetime) * time.Second)
+	dbSql.SetConnMaxLifetime(config.PoolingConnection.MaxLifetime)
 	db.Config.NamingStrategy = schema.NamingStrategy{}
 
 	sqlClient = db
@@ -0,0 +1,186 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const (
+	sqlRowsAffectedKey = attribute.Key("db.rows_affected")
+	// db.user is no longer part of the semantic conventions
+	sqlUserKey = attribute.Key("db.user")
+
+	sqlPluginName   = "otel"
+	sqlStartKey     = "otel:start"
+	sqlParentKey    = "otel:parent"
+	sqlCallbackName = "otel"
+
+	defaultSqlSlowThreshold = 3 * time.Second
+)
+
+// sqlLiteral matches placeholders, string and number literals of a statement
+var sqlLiteral = regexp.MustCompile(`\$\d+|'(?:[^']|'')*'|\b\d+(?:\.\d+)?\b`)
+
+type sqlSpanKey struct{}
+
+// sqlPlugin traces and measures every gorm statement
+type sqlPlugin struct {
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+	attrs    []attribute.KeyValue
+}
+
+// newOperationDuration is shared by the sql and redis hooks, instruments of the same name must have the same description and unit
+func newOperationDuration(meter metric.Meter) (metric.Float64Histogram, error) {
+	return meter.Float64Histogram(
+		"db.client.operation.duration",
+		metric.WithDescription("Duration of database client operations"),
+		metric.WithUnit("s"),
+	)
+}
+
+func newSqlPlugin(name string, database string, user string) (*sqlPlugin, error) {
+	duration, err := newOperationDuration(otel.Meter(name))
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqlPlugin{
+		tracer:   otel.Tracer(name),
+		duration: duration,
+		attrs: []attribute.KeyValue{
+			semconv.DBSystemPostgreSQL,
+			semconv.DBNamespace(database),
+			sqlUserKey.String(user),
+		},
+	}, nil
+}
+
+func (p *sqlPlugin) Name() string {
+	return sqlPluginName
+}
+
+func (p *sqlPlugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+
+	// Row and raw statements take the operation from the SQL
+	return errors.Join(
+		callback.Create().Before("gorm:create").Register(sqlCallbackName+":before_create", p.before),
+		callback.Create().After("gorm:create").Register(sqlCallbackName+":after_create", p.after("INSERT")),
+		callback.Query().Before("gorm:query").Register(sqlCallbackName+":before_query", p.before),
+		callback.Query().After("gorm:query").Register(sqlCallbackName+":after_query", p.after("SELECT")),
+		callback.Update().Before("gorm:update").Register(sqlCallbackName+":before_update", p.before),
+		callback.Update().After("gorm:update").Register(sqlCallbackName+":after_update", p.after("UPDATE")),
+		callback.Delete().Before("gorm:delete").Register(sqlCallbackName+":before_delete", p.before),
+		callback.Delete().After("gorm:delete").Register(sqlCallbackName+":after_delete", p.after("DELETE")),
+		callback.Row().Before("gorm:row").Register(sqlCallbackName+":before_row", p.before),
+		callback.Row().After("gorm:row").Register(sqlCallbackName+":after_row", p.after("")),
+		callback.Raw().Before("gorm:raw").Register(sqlCallbackName+":before_raw", p.before),
+		callback.Raw().After("gorm:raw").Register(sqlCallbackName+":after_raw", p.after("")),
+	)
+}
+
+func (p *sqlPlugin) before(db *gorm.DB) {
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	db.InstanceSet(sqlParentKey, ctx)
+
+	ctx, span := p.tracer.Start(ctx, "sql",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(p.attrs...),
+	)
+
+	db.Statement.Context = context.WithValue(ctx, sqlSpanKey{}, span)
+	db.InstanceSet(sqlStartKey, time.Now())
+}
+
+// after names the span once the statement is built and records the duration metric
+func (p *sqlPlugin) after(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		p.end(db, operation)
+	}
+}
+
+func (p *sqlPlugin) end(db *gorm.DB, operation string) {
+	span, ok := db.Statement.Context.Value(sqlSpanKey{}).(trace.Span)
+	if !ok {
+		return
+	}
+
+	var elapsed time.Duration
+	if start, ok := db.InstanceGet(sqlStartKey); ok {
+		elapsed = time.Since(start.(time.Time))
+	}
+
+	// Bound values are never added to the span, literals of raw statements are masked
+	statement := sanitizeSql(db.Statement.SQL.String())
+	if operation == "" {
+		operation = sqlOperation(statement)
+	}
+	table := db.Statement.Table
+
+	name := "sql." + strings.ToLower(operation)
+	if table != "" {
+		name += " " + table
+	}
+	span.SetName(name)
+
+	attrs := []attribute.KeyValue{semconv.DBOperationName(operation)}
+	if table != "" {
+		attrs = append(attrs, semconv.DBCollectionName(table))
+	}
+
+	span.SetAttributes(attrs...)
+	span.SetAttributes(
+		semconv.DBQueryText(statement),
+		sqlRowsAffectedKey.Int64(db.Statement.RowsAffected),
+	)
+
+	// A missing record is a normal result and not an error
+	if err := db.Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+
+	attrs = append(attrs, p.attrs...)
+	p.duration.Record(db.Statement.Context, elapsed.Seconds(), metric.WithAttributes(attrs...))
+
+	// Later statements of the same session must not become children of the ended span
+	if parent, ok := db.InstanceGet(sqlParentKey); ok {
+		db.Statement.Context = parent.(context.Context)
+	}
+}
+
+// sqlOperation returns the verb of a raw statement
+func sqlOperation(statement string) string {
+	fields := strings.Fields(statement)
+	if len(fields) == 0 {
+		return "RAW"
+	}
+	return strings.ToUpper(fields[0])
+}
+
+// sanitizeSql replaces the literals of a statement with ? and keeps its placeholders
+func sanitizeSql(statement string) string {
+	return sqlLiteral.ReplaceAllStringFunc(statement, func(match string) string {
+		if strings.HasPrefix(match, "$") {
+			return match
+		}
+		return "?"
+	})
+}
@@ -0,0 +1,225 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/alfin-efendy/helper-go/config"
+	"github.com/alfin-efendy/helper-go/database"
+	"github.com/alfin-efendy/helper-go/logger"
+	"github.com/alfin-efendy/helper-go/otel"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	otelApi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	payloadField = "payload"
+	headersField = "headers"
+
+	deadLetterSuffix = ":dead-letter"
+	messagingSystem  = "redis"
+
+	defaultMaxRetries   = 3
+	defaultBatchSize    = 10
+	defaultBlockTimeout = 5 * time.Second
+	defaultClaimIdle    = 30 * time.Second
+)
+
+var (
+	eventBusInstance EventBus
+
+	ErrNotInitialized = errors.New("event bus is not initialized")
+)
+
+// Message is a single event read from a topic
+type Message struct {
+	ID      string
+	Topic   string
+	Payload []byte
+	Headers map[string]string
+	// Retry is the number of times the message has been delivered before
+	Retry int64
+}
+
+// Bind decodes the JSON payload of the message into v
+func (m Message) Bind(v interface{}) error {
+	return json.Unmarshal(m.Payload, v)
+}
+
+// Handler processes a message, returning an error leaves the message pending for retry
+type Handler func(ctx context.Context, msg Message) error
+
+type EventBus interface {
+	Publish(ctx context.Context, topic string, msg interface{}) error
+	Subscribe(topic string, group string, handler Handler) error
+	Close() error
+}
+
+type eventBus struct {
+	client       *redis.Client
+	consumer     string
+	maxRetries   int64
+	batchSize    int64
+	maxLen       int64
+	blockTimeout time.Duration
+	claimIdle    time.Duration
+	// trustIdentity keeps the identity baggage of the events, it is dropped by default
+	trustIdentity bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewEventBus(client *redis.Client, consumer string) EventBus {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &eventBus{
+		client:       client,
+		consumer:     consumer,
+		maxRetries:   defaultMaxRetries,
+		batchSize:    defaultBatchSize,
+		blockTimeout: defaultBlockTimeout,
+		claimIdle:    defaultClaimIdle,
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+}
+
+func Init(ctx context.Context) {
+	ctx, span := otel.Trace(ctx)
+	defer span.End()
+
+	redisClient := database.GetRedisClient()
+	if redisClient == nil {
+		logger.Warn(ctx, "❌ Event bus is disabled, redis client is not initialized")
+		return
+	}
+
+	conf := config.Config.EventBus
+
+	consumer, _ := os.Hostname()
+	consumer = fmt.Sprintf("%s-%s", consumer, uuid.NewString())
+	if conf != nil && conf.Consumer != "" {
+		consumer = conf.Consumer
+	}
+
+	bus := NewEventBus(redisClient, consumer).(*eventBus)
+
+	if conf != nil {
+		if conf.MaxRetries > 0 {
+			bus.maxRetries = int64(conf.MaxRetries)
+		}
+		if conf.BatchSize > 0 {
+			bus.batchSize = conf.BatchSize
+		}
+		if conf.MaxLen > 0 {
+			bus.maxLen = conf.MaxLen
+		}
+		if conf.BlockTimeout > 0 {
+			bus.blockTimeout = conf.BlockTimeout
+		}
+		if conf.ClaimIdle > 0 {
+			bus.claimIdle = conf.ClaimIdle
+		}
+		bus.trustIdentity = conf.TrustIdentityBaggage
+	}
+
+	eventBusInstance = bus
+
+	logger.Info(ctx, "✅ Event bus initialized")
+}
+
+func (b *eventBus) Publish(ctx context.Context, topic string, msg interface{}) error {
+	ctx, span := otel.Trace(ctx,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKey.String(messagingSystem),
+			semconv.MessagingDestinationName(topic),
+			semconv.MessagingOperationTypePublish,
+		),
+	)
+	defer span.End()
+
+	var payload []byte
+	switch v := msg.(type) {
+	case []byte:
+		payload = v
+	case string:
+		payload = []byte(v)
+	default:
+		var err error
+		if payload, err = json.Marshal(v); err != nil {
+			logger.Error(ctx, err, "Failed to encode event payload")
+			return err
+		}
+	}
+
+	// Inject the trace context so the consumer span joins the producer trace
+	headers := make(map[string]string)
+	otelApi.GetTextMapPropagator().Inject(ctx, propagation.MapCarrier(headers))
+
+	encodedHeaders, err := json.Marshal(headers)
+	if err != nil {
+		logger.Error(ctx, err, "Failed to encode event headers")
+		return err
+	}
+
+	args := &redis.XAddArgs{
+		Stream: topic,
+		Values: map[string]interface{}{
+			payloadField: payload,
+			headersField: encodedHeaders,
+		},
+	}
+	if b.maxLen > 0 {
+		args.MaxLen = b.maxLen
+		args.Approx = true
+	}
+
+	if err := b.client.XAdd(ctx, args).Err(); err != nil {
+		logger.Error(ctx, err, fmt.Sprintf("Failed to publish event to %s", topic))
+		return err
+	}
+
+	return nil
+}
+
+// Publish sends msg to the topic stream, msg is encoded as JSON unless it is []byte or string
+func Publish(ctx context.Context, topic string, msg interface{}) error {
+	if eventBusInstance == nil {
+		return ErrNotInitialized
+	}
+	return eventBusInstance.Publish(ctx, topic, msg)
+}
+
+// Subscribe consumes the topic stream as a member of the consumer group
+func Subscribe(topic string, group string, handler Handler) error {
+	if eventBusInstance == nil {
+		return ErrNotInitialized
+	}
+	return eventBusInstance.Subscribe(topic, group, handler)
+}
+
+func (b *eventBus) Close() error {
+	b.cancel()
+	b.wg.Wait()
+	return nil
+}
+
+// Close stops every subscriber and waits for in-flight handlers to return
+func Close() error {
+	if eventBusInstance == nil {
+		return nil
+	}
+	return eventBusInstance.Close()
+}
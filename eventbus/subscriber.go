@@ -0,0 +1,222 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	helperCtx "github.com/alfin-efendy/helper-go/context"
+	"github.com/alfin-efendy/helper-go/logger"
+	"github.com/alfin-efendy/helper-go/otel"
+	"github.com/redis/go-redis/v9"
+	otelApi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+func (b *eventBus) Subscribe(topic string, group string, handler Handler) error {
+	// Create the consumer group, the stream is created when it does not exist
+	err := b.client.XGroupCreateMkStream(b.ctx, topic, group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		logger.Error(b.ctx, err, fmt.Sprintf("Failed to create consumer group %s on %s", group, topic))
+		return err
+	}
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.consume(topic, group, handler)
+	}()
+
+	return nil
+}
+
+// consume reads new messages for the group until the event bus is closed
+func (b *eventBus) consume(topic string, group string, handler Handler) {
+	lastClaim := time.Now()
+
+	for b.ctx.Err() == nil {
+		streams, err := b.client.XReadGroup(b.ctx, &redis.XReadGroupArgs{
+			Group:    group,
+			Consumer: b.consumer,
+			Streams:  []string{topic, ">"},
+			Count:    b.batchSize,
+			Block:    b.blockTimeout,
+		}).Result()
+
+		switch {
+		case b.ctx.Err() != nil:
+			return
+		case errors.Is(err, redis.Nil):
+		case err != nil:
+			logger.Error(b.ctx, err, fmt.Sprintf("Failed to read events from %s", topic))
+			if !b.wait(time.Second) {
+				return
+			}
+		default:
+			for _, stream := range streams {
+				for _, message := range stream.Messages {
+					b.handle(topic, group, message, 0, handler)
+				}
+			}
+		}
+
+		// Retry messages left pending by failed handlers
+		if time.Since(lastClaim) >= b.claimIdle {
+			b.retryPending(topic, group, handler)
+			lastClaim = time.Now()
+		}
+	}
+}
+
+// retryPending claims idle pending messages and moves exhausted ones to the dead-letter stream
+func (b *eventBus) retryPending(topic string, group string, handler Handler) {
+	pending, err := b.client.XPendingExt(b.ctx, &redis.XPendingExtArgs{
+		Stream: topic,
+		Group:  group,
+		Idle:   b.claimIdle,
+		Start:  "-",
+		End:    "+",
+		Count:  b.batchSize,
+	}).Result()
+	if err != nil {
+		if b.ctx.Err() == nil {
+			logger.Error(b.ctx, err, fmt.Sprintf("Failed to read pending events from %s", topic))
+		}
+		return
+	}
+
+	for _, p := range pending {
+		messages, err := b.client.XClaim(b.ctx, &redis.XClaimArgs{
+			Stream:   topic,
+			Group:    group,
+			Consumer: b.consumer,
+			MinIdle:  b.claimIdle,
+			Messages: []string{p.ID},
+		}).Result()
+		if err != nil {
+			logger.Error(b.ctx, err, fmt.Sprintf("Failed to claim event %s from %s", p.ID, topic))
+			continue
+		}
+
+		for _, message := range messages {
+			if p.RetryCount > b.maxRetries {
+				b.deadLetter(topic, group, message, p.RetryCount)
+				continue
+			}
+
+			b.handle(topic, group, message, p.RetryCount, handler)
+		}
+	}
+}
+
+// handle runs the handler inside a consumer span linked to the producer span
+func (b *eventBus) handle(topic string, group string, message redis.XMessage, retry int64, handler Handler) {
+	msg := decodeMessage(topic, message, retry)
+
+	parent := otelApi.GetTextMapPropagator().Extract(b.ctx, propagation.MapCarrier(msg.Headers))
+
+	// Any publisher can set the identity baggage, the context getters fall back to it
+	if !b.trustIdentity {
+		parent = helperCtx.DropIdentityBaggage(parent)
+	}
+
+	ctx, span := otel.Trace(parent,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithLinks(trace.LinkFromContext(parent)),
+		trace.WithAttributes(
+			semconv.MessagingSystemKey.String(messagingSystem),
+			semconv.MessagingDestinationName(topic),
+			semconv.MessagingOperationTypeDeliver,
+			semconv.MessagingMessageID(msg.ID),
+			attribute.String("messaging.consumer.group", group),
+		),
+	)
+	defer span.End()
+
+	if err := b.safeHandle(ctx, handler, msg); err != nil {
+		logger.Error(ctx, err, fmt.Sprintf("Failed to handle event %s from %s", msg.ID, topic))
+		return
+	}
+
+	if err := b.client.XAck(ctx, topic, group, msg.ID).Err(); err != nil {
+		logger.Error(ctx, err, fmt.Sprintf("Failed to acknowledge event %s from %s", msg.ID, topic))
+	}
+}
+
+// safeHandle turns a panicking handler into a retryable error
+func (b *eventBus) safeHandle(ctx context.Context, handler Handler, msg Message) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("event handler panic: %v", r)
+		}
+	}()
+
+	return handler(ctx, msg)
+}
+
+// deadLetter copies the message to the dead-letter stream and acknowledges it
+func (b *eventBus) deadLetter(topic string, group string, message redis.XMessage, retry int64) {
+	values := make(map[string]interface{}, len(message.Values)+3)
+	for k, v := range message.Values {
+		values[k] = v
+	}
+	values["id"] = message.ID
+	values["group"] = group
+	values["retry"] = retry
+
+	ctx := b.ctx
+	deadLetterTopic := topic + deadLetterSuffix
+
+	if err := b.client.XAdd(ctx, &redis.XAddArgs{Stream: deadLetterTopic, Values: values}).Err(); err != nil {
+		logger.Error(ctx, err, fmt.Sprintf("Failed to move event %s to %s", message.ID, deadLetterTopic))
+		return
+	}
+
+	if err := b.client.XAck(ctx, topic, group, message.ID).Err(); err != nil {
+		logger.Error(ctx, err, fmt.Sprintf("Failed to acknowledge event %s from %s", message.ID, topic))
+		return
+	}
+
+	logger.Warn(ctx, fmt.Sprintf("Event %s from %s moved to %s", message.ID, topic, deadLetterTopic),
+		zap.Int64("retry", retry),
+	)
+}
+
+func decodeMessage(topic string, message redis.XMessage, retry int64) Message {
+	msg := Message{
+		ID:      message.ID,
+		Topic:   topic,
+		Headers: make(map[string]string),
+		Retry:   retry,
+	}
+
+	if payload, ok := message.Values[payloadField].(string); ok {
+		msg.Payload = []byte(payload)
+	}
+
+	if headers, ok := message.Values[headersField].(string); ok {
+		_ = json.Unmarshal([]byte(headers), &msg.Headers)
+	}
+
+	return msg
+}
+
+// wait pauses for the delay and reports false when the event bus is closed in the meantime
+func (b *eventBus) wait(delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-b.ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
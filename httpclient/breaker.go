@@ -0,0 +1,112 @@
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuit counts the consecutive failures of a host
+type circuit struct {
+	state    circuitState
+	failures int
+	openedAt time.Time
+}
+
+// breakerTransport rejects the calls to a host after consecutive failures until the open timeout,
+// then lets a single call through to probe the host
+type breakerTransport struct {
+	next             http.RoundTripper
+	failureThreshold int
+	openTimeout      time.Duration
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+func (t *breakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	if !t.allow(host) {
+		return nil, ErrCircuitOpen
+	}
+
+	resp, err := t.next.RoundTrip(req)
+
+	// A cancelled caller says nothing about the health of the host
+	if err != nil && req.Context().Err() != nil {
+		t.release(host)
+		return resp, err
+	}
+
+	t.report(host, err == nil && resp.StatusCode < http.StatusInternalServerError)
+	return resp, err
+}
+
+func (t *breakerTransport) allow(host string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	c, ok := t.circuits[host]
+	if !ok {
+		return true
+	}
+
+	switch c.state {
+	case circuitOpen:
+		if time.Since(c.openedAt) < t.openTimeout {
+			return false
+		}
+		c.state = circuitHalfOpen
+		return true
+	case circuitHalfOpen:
+		// Only the probe is in flight
+		return false
+	default:
+		return true
+	}
+}
+
+// release lets another call probe the host when the probe was cancelled
+func (t *breakerTransport) release(host string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if c, ok := t.circuits[host]; ok && c.state == circuitHalfOpen {
+		c.state = circuitOpen
+	}
+}
+
+func (t *breakerTransport) report(host string, success bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	c, ok := t.circuits[host]
+	if success {
+		if ok {
+			delete(t.circuits, host)
+		}
+		return
+	}
+
+	if !ok {
+		c = &circuit{}
+		t.circuits[host] = c
+	}
+
+	c.failures++
+	if c.state == circuitHalfOpen || c.failures >= t.failureThreshold {
+		c.state = circuitOpen
+		c.openedAt = time.Now()
+	}
+}
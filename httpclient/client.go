@@ -0,0 +1,207 @@
+package httpclient
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/alfin-efendy/helper-go/config"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+const (
+	defaultTimeout               = 30 * time.Second
+	defaultDialTimeout           = 5 * time.Second
+	defaultTLSHandshakeTimeout   = 5 * time.Second
+	defaultResponseHeaderTimeout = 10 * time.Second
+	defaultIdleConnTimeout       = 90 * time.Second
+	defaultMaxIdleConns          = 100
+	defaultMaxIdleConnsPerHost   = 10
+
+	defaultMaxAttempts      = 3
+	defaultInitialBackoff   = 100 * time.Millisecond
+	defaultMaxBackoff       = 2 * time.Second
+	defaultFailureThreshold = 5
+	defaultOpenTimeout      = 30 * time.Second
+)
+
+var (
+	clientOnce     sync.Once
+	clientInstance *http.Client
+)
+
+type options struct {
+	timeout          time.Duration
+	base             http.RoundTripper
+	maxAttempts      int
+	initialBackoff   time.Duration
+	maxBackoff       time.Duration
+	failureThreshold int
+	openTimeout      time.Duration
+}
+
+// Option overrides the settings read from httpClient config
+type Option func(o *options)
+
+// WithTimeout limits a whole call including retries, 0 disables the limit
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// WithTransport replaces the base transport, it is still traced, retried and logged
+func WithTransport(base http.RoundTripper) Option {
+	return func(o *options) {
+		o.base = base
+	}
+}
+
+// WithRetry sets the attempts of idempotent calls, 1 disables retries
+func WithRetry(maxAttempts int, initialBackoff, maxBackoff time.Duration) Option {
+	return func(o *options) {
+		o.maxAttempts = maxAttempts
+		o.initialBackoff = initialBackoff
+		o.maxBackoff = maxBackoff
+	}
+}
+
+// WithCircuitBreaker sets the consecutive failures that open the circuit of a host, 0 disables it
+func WithCircuitBreaker(failureThreshold int, openTimeout time.Duration) Option {
+	return func(o *options) {
+		o.failureThreshold = failureThreshold
+		o.openTimeout = openTimeout
+	}
+}
+
+func defaultOptions() *options {
+	o := &options{
+		timeout:          defaultTimeout,
+		maxAttempts:      defaultMaxAttempts,
+		initialBackoff:   defaultInitialBackoff,
+		maxBackoff:       defaultMaxBackoff,
+		failureThreshold: defaultFailureThreshold,
+		openTimeout:      defaultOpenTimeout,
+	}
+
+	conf := config.Config.HttpClient
+	if conf == nil {
+		return o
+	}
+
+	if conf.Timeout > 0 {
+		o.timeout = conf.Timeout
+	}
+	if retry := conf.Retry; retry != nil {
+		if retry.MaxAttempts > 0 {
+			o.maxAttempts = retry.MaxAttempts
+		}
+		if retry.InitialBackoff > 0 {
+			o.initialBackoff = retry.InitialBackoff
+		}
+		if retry.MaxBackoff > 0 {
+			o.maxBackoff = retry.MaxBackoff
+		}
+	}
+	if breaker := conf.CircuitBreaker; breaker != nil {
+		if breaker.FailureThreshold != 0 {
+			o.failureThreshold = breaker.FailureThreshold
+		}
+		if breaker.OpenTimeout > 0 {
+			o.openTimeout = breaker.OpenTimeout
+		}
+	}
+
+	return o
+}
+
+// newBaseTransport returns a transport with the timeouts and pool sizes of httpClient config
+func newBaseTransport() *http.Transport {
+	dialTimeout := defaultDialTimeout
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSHandshakeTimeout = defaultTLSHandshakeTimeout
+	transport.ResponseHeaderTimeout = defaultResponseHeaderTimeout
+	transport.IdleConnTimeout = defaultIdleConnTimeout
+	transport.MaxIdleConns = defaultMaxIdleConns
+	transport.MaxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+
+	if conf := config.Config.HttpClient; conf != nil {
+		if conf.DialTimeout > 0 {
+			dialTimeout = conf.DialTimeout
+		}
+		if conf.TLSHandshakeTimeout > 0 {
+			transport.TLSHandshakeTimeout = conf.TLSHandshakeTimeout
+		}
+		if conf.ResponseHeaderTimeout > 0 {
+			transport.ResponseHeaderTimeout = conf.ResponseHeaderTimeout
+		}
+		if conf.IdleConnTimeout > 0 {
+			transport.IdleConnTimeout = conf.IdleConnTimeout
+		}
+		if conf.MaxIdleConns > 0 {
+			transport.MaxIdleConns = conf.MaxIdleConns
+		}
+		if conf.MaxIdleConnsPerHost > 0 {
+			transport.MaxIdleConnsPerHost = conf.MaxIdleConnsPerHost
+		}
+	}
+
+	transport.DialContext = (&net.Dialer{
+		Timeout:   dialTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+
+	return transport
+}
+
+// New returns a client forwarding the trace context and the caller identity,
+// idempotent calls are retried and failing hosts are short-circuited
+func New(opts ...Option) *http.Client {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	base := o.base
+	if base == nil {
+		base = newBaseTransport()
+	}
+
+	// Every attempt is its own client span
+	var transport http.RoundTripper = otelhttp.NewTransport(base)
+
+	if o.maxAttempts > 1 {
+		transport = &retryTransport{
+			next:           transport,
+			maxAttempts:    o.maxAttempts,
+			initialBackoff: o.initialBackoff,
+			maxBackoff:     o.maxBackoff,
+		}
+	}
+
+	// A retried call counts as a single failure of the host
+	if o.failureThreshold > 0 {
+		transport = &breakerTransport{
+			next:             transport,
+			failureThreshold: o.failureThreshold,
+			openTimeout:      o.openTimeout,
+			circuits:         make(map[string]*circuit),
+		}
+	}
+
+	transport = &headerTransport{next: &loggingTransport{next: transport}}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   o.timeout,
+	}
+}
+
+// GetClient returns the shared client built from httpClient config
+func GetClient() *http.Client {
+	clientOnce.Do(func() {
+		clientInstance = New()
+	})
+	return clientInstance
+}
@@ -0,0 +1,108 @@
+package httpclient
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	helperCtx "github.com/alfin-efendy/helper-go/context"
+	"github.com/alfin-efendy/helper-go/logger"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.uber.org/zap"
+)
+
+const (
+	UserIdHeader    = "X-User-Id"
+	FullNameHeader  = "X-Full-Name"
+	RealmHeader     = "X-Realm"
+	RequestIdHeader = "X-Request-Id"
+
+	baggageHeader = "Baggage"
+
+	loggerName = "httpclient"
+)
+
+// headerTransport forwards the values of the context package as the headers and baggage read by the REST API server
+type headerTransport struct {
+	next http.RoundTripper
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	headers := map[string]string{
+		UserIdHeader:    helperCtx.GetUserId(ctx),
+		FullNameHeader:  helperCtx.GetFullName(ctx),
+		RealmHeader:     helperCtx.GetRealm(ctx),
+		RequestIdHeader: helperCtx.GetRequestId(ctx),
+	}
+
+	// RoundTrippers must not modify the request of the caller
+	cloned := false
+	for name, value := range headers {
+		if value == "" || req.Header.Get(name) != "" {
+			continue
+		}
+		if !cloned {
+			req = req.Clone(ctx)
+			cloned = true
+		}
+		req.Header.Set(name, value)
+	}
+
+	// The baggage is also injected by otelhttp, but only once OpenTelemetry is enabled
+	if req.Header.Get(baggageHeader) == "" && baggage.FromContext(ctx).Len() > 0 {
+		if !cloned {
+			req = req.Clone(ctx)
+		}
+		propagation.Baggage{}.Inject(ctx, propagation.HeaderCarrier(req.Header))
+	}
+
+	return t.next.RoundTrip(req)
+}
+
+// loggingTransport logs every call once, after its retries
+type loggingTransport struct {
+	next http.RoundTripper
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	log := logger.Named(loggerName)
+	redactor := logger.GetRedactor()
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	latency := time.Since(start)
+
+	url := *req.URL
+	url.RawQuery = redactor.Query(url.RawQuery)
+	url.User = nil
+
+	fields := []zap.Field{
+		zap.String("Method", req.Method),
+		zap.String("Url", url.String()),
+		zap.Any("Headers", redactor.Headers(req.Header)),
+		zap.String("Latency", latency.String()),
+	}
+
+	if err != nil {
+		log.Error(ctx, err, append(fields, zap.String("message", fmt.Sprintf("[%v] %s %s failed", latency, req.Method, url.String())))...)
+		return resp, err
+	}
+
+	fields = append(fields,
+		zap.Int("Status", resp.StatusCode),
+		zap.Any("ResponseHeaders", redactor.Headers(resp.Header)),
+	)
+
+	msg := fmt.Sprintf("[%v] [%v] %s %s", latency, resp.StatusCode, req.Method, url.String())
+	if resp.StatusCode >= http.StatusInternalServerError {
+		log.Warn(ctx, msg, fields...)
+	} else {
+		log.Info(ctx, msg, fields...)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+
+	helperCtx "github.com/alfin-efendy/helper-go/context"
+)
+
+const (
+	UserIdKey    = "userId"
+	FullNameKey  = "fullName"
+	RealmKey     = "realm"
+	RequestIdKey = "requestId"
+	RouteKey     = "route"
+	ClientIPKey  = "clientIP"
+)
+
+var (
+	extractorsMu sync.RWMutex
+	extractors   = []func(ctx context.Context) zap.Field{
+		ContextString(UserIdKey, helperCtx.GetUserId),
+		ContextString(FullNameKey, helperCtx.GetFullName),
+		ContextString(RealmKey, helperCtx.GetRealm),
+		ContextString(RequestIdKey, helperCtx.GetRequestId),
+		ContextString(RouteKey, helperCtx.GetRoute),
+		ContextString(ClientIPKey, helperCtx.GetClientIP),
+	}
+)
+
+// RegisterContextExtractor adds fields read from the context to every log entry,
+// extractors share the signature of the gorm logger custom fields
+func RegisterContextExtractor(fields ...func(ctx context.Context) zap.Field) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+
+	extractors = append(extractors, fields...)
+}
+
+// ContextString custom string field read from the context, empty values are skipped
+func ContextString(key string, get func(ctx context.Context) string) func(ctx context.Context) zap.Field {
+	return func(ctx context.Context) zap.Field {
+		if value := get(ctx); value != "" {
+			return zap.String(key, value)
+		}
+		return zap.Skip()
+	}
+}
+
+// contextFields runs every registered extractor on the context
+func contextFields(ctx context.Context) []zap.Field {
+	if ctx == nil {
+		return nil
+	}
+
+	extractorsMu.RLock()
+	defer extractorsMu.RUnlock()
+
+	fields := make([]zap.Field, 0, len(extractors))
+	for _, extractor := range extractors {
+		fields = append(fields, extractor(ctx))
+	}
+	return fields
+}
+
+func addContextEntries(ctx context.Context, logger *zap.Logger) *zap.Logger {
+	return logger.With(contextFields(ctx)...)
+}
@@ -0,0 +1,187 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alfin-efendy/helper-go/config/model"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	TimeFormatRFC3339     = "rfc3339"
+	TimeFormatRFC3339Nano = "rfc3339nano"
+	TimeFormatISO8601     = "iso8601"
+	TimeFormatEpoch       = "epoch"
+	TimeFormatEpochMillis = "epochMillis"
+	TimeFormatEpochNanos  = "epochNanos"
+
+	FieldKeysDefault = "default"
+	FieldKeysECS     = "ecs"
+	FieldKeysGCP     = "gcp"
+	FieldKeysDatadog = "datadog"
+
+	consoleTimeLayout = "2006-01-02 15:04:05"
+)
+
+// fieldKeyConventions are the entry keys expected by log backends
+var fieldKeyConventions = map[string]zapcore.EncoderConfig{
+	FieldKeysECS: {
+		TimeKey:       "@timestamp",
+		LevelKey:      "log.level",
+		MessageKey:    "message",
+		NameKey:       "log.logger",
+		StacktraceKey: "error.stack_trace",
+	},
+	FieldKeysGCP: {
+		TimeKey:       "timestamp",
+		LevelKey:      "severity",
+		MessageKey:    "message",
+		NameKey:       "logger",
+		StacktraceKey: "stack_trace",
+	},
+	FieldKeysDatadog: {
+		TimeKey:       "timestamp",
+		LevelKey:      "status",
+		MessageKey:    "message",
+		NameKey:       "logger.name",
+		StacktraceKey: "error.stack",
+	},
+}
+
+// entryFormat holds the time and key settings shared by every sink
+type entryFormat struct {
+	location   *time.Location
+	timeFormat string
+	fieldKeys  string
+	keys       zapcore.EncoderConfig
+}
+
+func newEntryFormat(conf *model.Config) (*entryFormat, error) {
+	format := &entryFormat{
+		location:   time.Local,
+		timeFormat: conf.Log.TimeFormat,
+		fieldKeys:  conf.Log.FieldKeys,
+	}
+
+	if conf.Log.TimeZone != "" {
+		location, err := time.LoadLocation(conf.Log.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid log time zone %q: %w", conf.Log.TimeZone, err)
+		}
+		format.location = location
+	}
+
+	switch format.fieldKeys {
+	case "", FieldKeysDefault:
+	default:
+		keys, ok := fieldKeyConventions[format.fieldKeys]
+		if !ok {
+			return nil, fmt.Errorf("log field keys %s is not supported", format.fieldKeys)
+		}
+		format.keys = keys
+	}
+
+	if k := conf.Log.Keys; k != nil {
+		format.keys.TimeKey = firstNonEmpty(k.Time, format.keys.TimeKey)
+		format.keys.LevelKey = firstNonEmpty(k.Level, format.keys.LevelKey)
+		format.keys.MessageKey = firstNonEmpty(k.Message, format.keys.MessageKey)
+		format.keys.NameKey = firstNonEmpty(k.Name, format.keys.NameKey)
+		format.keys.StacktraceKey = firstNonEmpty(k.Stacktrace, format.keys.StacktraceKey)
+	}
+
+	return format, nil
+}
+
+// jsonConfig returns the production encoder config with the configured keys and time format
+func (f *entryFormat) jsonConfig() zapcore.EncoderConfig {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.TimeKey = firstNonEmpty(f.keys.TimeKey, encoderConfig.TimeKey)
+	encoderConfig.LevelKey = firstNonEmpty(f.keys.LevelKey, encoderConfig.LevelKey)
+	encoderConfig.MessageKey = firstNonEmpty(f.keys.MessageKey, encoderConfig.MessageKey)
+	encoderConfig.NameKey = firstNonEmpty(f.keys.NameKey, encoderConfig.NameKey)
+	encoderConfig.StacktraceKey = firstNonEmpty(f.keys.StacktraceKey, encoderConfig.StacktraceKey)
+	// The conventions ingest the time as a string, epoch is kept for the default keys
+	fallback := TimeFormatEpoch
+	if _, ok := fieldKeyConventions[f.fieldKeys]; ok {
+		fallback = TimeFormatRFC3339Nano
+	}
+	encoderConfig.EncodeTime = f.timeEncoder(fallback)
+
+	if f.fieldKeys == FieldKeysGCP {
+		encoderConfig.EncodeLevel = gcpLevelEncoder
+	}
+
+	return encoderConfig
+}
+
+// consoleConfig returns the development encoder config, it keeps a readable time unless a format is set
+func (f *entryFormat) consoleConfig() zapcore.EncoderConfig {
+	encoderConfig := zap.NewDevelopmentEncoderConfig()
+	encoderConfig.EncodeTime = f.timeEncoder(consoleTimeLayout)
+	return encoderConfig
+}
+
+// timeEncoder encodes times in the configured zone and format, fallback is used when no format is set
+func (f *entryFormat) timeEncoder(fallback string) zapcore.TimeEncoder {
+	format := f.timeFormat
+	if format == "" {
+		format = fallback
+	}
+
+	var encode zapcore.TimeEncoder
+	switch strings.ToLower(format) {
+	case strings.ToLower(TimeFormatRFC3339):
+		encode = zapcore.RFC3339TimeEncoder
+	case strings.ToLower(TimeFormatRFC3339Nano):
+		encode = zapcore.RFC3339NanoTimeEncoder
+	case strings.ToLower(TimeFormatISO8601):
+		encode = zapcore.ISO8601TimeEncoder
+	case strings.ToLower(TimeFormatEpoch):
+		encode = zapcore.EpochTimeEncoder
+	case strings.ToLower(TimeFormatEpochMillis):
+		encode = zapcore.EpochMillisTimeEncoder
+	case strings.ToLower(TimeFormatEpochNanos):
+		encode = zapcore.EpochNanosTimeEncoder
+	default:
+		encode = zapcore.TimeEncoderOfLayout(format)
+	}
+
+	location := f.location
+	return func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+		encode(t.In(location), enc)
+	}
+}
+
+// gcpLevelEncoder writes the severity names of Cloud Logging
+func gcpLevelEncoder(lvl zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	switch lvl {
+	case zapcore.DebugLevel:
+		enc.AppendString("DEBUG")
+	case zapcore.InfoLevel:
+		enc.AppendString("INFO")
+	case zapcore.WarnLevel:
+		enc.AppendString("WARNING")
+	case zapcore.ErrorLevel:
+		enc.AppendString("ERROR")
+	case zapcore.DPanicLevel:
+		enc.AppendString("CRITICAL")
+	case zapcore.PanicLevel:
+		enc.AppendString("ALERT")
+	case zapcore.FatalLevel:
+		enc.AppendString("EMERGENCY")
+	default:
+		enc.AppendString("DEFAULT")
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -60,6 +60,14 @@ func (l *ZapGormLogger) LogMode(level gormLogger.LogLevel) gormLogger.Interface
 	return &newLogger
 }
 
+// ParamsFilter drops bound values from logged SQL when ParameterizedQueries is set
+func (l *ZapGormLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if l.ParameterizedQueries {
+		return sql, nil
+	}
+	return sql, params
+}
+
 // Info print info
 func (l ZapGormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
 	if l.LogLevel >= gormLogger.Info {
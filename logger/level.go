@@ -0,0 +1,186 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var levels = &levelRegistry{
+	level:     zap.NewAtomicLevel(),
+	overrides: make(map[string]*levelOverride),
+}
+
+type levelOverride struct {
+	level zapcore.Level
+	timer *time.Timer
+}
+
+// levelRegistry holds the global level and the per logger name overrides
+type levelRegistry struct {
+	mu         sync.RWMutex
+	level      zap.AtomicLevel
+	configured zapcore.Level
+	timer      *time.Timer
+	// generation changes on every global level change, a revert timer that fired late does nothing
+	generation uint64
+	overrides  map[string]*levelOverride
+}
+
+// minLevel is the lowest level any logger may write
+func (r *levelRegistry) minLevel() zapcore.Level {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	min := r.level.Level()
+	for _, o := range r.overrides {
+		if o.level < min {
+			min = o.level
+		}
+	}
+	return min
+}
+
+// levelFor returns the level of the most specific override matching the logger name
+func (r *levelRegistry) levelFor(name string) zapcore.Level {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matched := ""
+	lvl := r.level.Level()
+	for key, o := range r.overrides {
+		if (name == key || strings.HasPrefix(name, key+".")) && len(key) > len(matched) {
+			matched = key
+			lvl = o.level
+		}
+	}
+	return lvl
+}
+
+// Enabled lets sinks without their own level follow the global level and overrides
+func (r *levelRegistry) Enabled(lvl zapcore.Level) bool {
+	return lvl >= r.minLevel()
+}
+
+// overrideCore drops entries below the level of their logger name
+type overrideCore struct {
+	zapcore.Core
+}
+
+func (c *overrideCore) Enabled(lvl zapcore.Level) bool {
+	return levels.Enabled(lvl) && c.Core.Enabled(lvl)
+}
+
+func (c *overrideCore) With(fields []zapcore.Field) zapcore.Core {
+	return &overrideCore{Core: c.Core.With(fields)}
+}
+
+func (c *overrideCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if entry.Level < levels.levelFor(entry.LoggerName) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
+
+// setConfiguredLevel sets the level read from config, temporary changes revert to it
+func setConfiguredLevel(lvl zapcore.Level) {
+	levels.mu.Lock()
+	defer levels.mu.Unlock()
+
+	if levels.timer != nil {
+		levels.timer.Stop()
+		levels.timer = nil
+	}
+
+	levels.generation++
+	levels.configured = lvl
+	levels.level.SetLevel(lvl)
+}
+
+// SetLevel changes the global level, a positive revertAfter restores the configured level after it elapses
+func SetLevel(lvl zapcore.Level, revertAfter time.Duration) {
+	levels.mu.Lock()
+	defer levels.mu.Unlock()
+
+	if levels.timer != nil {
+		levels.timer.Stop()
+		levels.timer = nil
+	}
+
+	levels.generation++
+	levels.level.SetLevel(lvl)
+
+	if revertAfter > 0 {
+		generation := levels.generation
+		levels.timer = time.AfterFunc(revertAfter, func() {
+			levels.mu.Lock()
+			defer levels.mu.Unlock()
+
+			if levels.generation != generation {
+				return
+			}
+
+			levels.generation++
+			levels.timer = nil
+			levels.level.SetLevel(levels.configured)
+		})
+	}
+}
+
+// SetNamedLevel overrides the level of a named logger and its children,
+// a positive revertAfter removes the override after it elapses
+func SetNamedLevel(name string, lvl zapcore.Level, revertAfter time.Duration) {
+	levels.mu.Lock()
+	defer levels.mu.Unlock()
+
+	if o, ok := levels.overrides[name]; ok && o.timer != nil {
+		o.timer.Stop()
+	}
+
+	override := &levelOverride{level: lvl}
+	if revertAfter > 0 {
+		override.timer = time.AfterFunc(revertAfter, func() {
+			levels.mu.Lock()
+			defer levels.mu.Unlock()
+
+			if levels.overrides[name] == override {
+				delete(levels.overrides, name)
+			}
+		})
+	}
+
+	levels.overrides[name] = override
+}
+
+// ResetNamedLevel removes the override of a named logger
+func ResetNamedLevel(name string) {
+	levels.mu.Lock()
+	defer levels.mu.Unlock()
+
+	if o, ok := levels.overrides[name]; ok {
+		if o.timer != nil {
+			o.timer.Stop()
+		}
+		delete(levels.overrides, name)
+	}
+}
+
+// GetNamedLevels returns the per logger name overrides
+func GetNamedLevels() map[string]zapcore.Level {
+	levels.mu.RLock()
+	defer levels.mu.RUnlock()
+
+	result := make(map[string]zapcore.Level, len(levels.overrides))
+	for name, o := range levels.overrides {
+		result[name] = o.level
+	}
+	return result
+}
+
+// GetAtomicLevel returns the global level shared by every logger
+func GetAtomicLevel() zap.AtomicLevel {
+	return levels.level
+}
@@ -3,25 +3,22 @@ package logger
 import (
 	"context"
 	"fmt"
-	"os"
 	"runtime"
 
 	"github.com/alfin-efendy/helper-go/config"
+	"github.com/alfin-efendy/helper-go/config/model"
 	"github.com/alfin-efendy/helper-go/utility"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
-	"gopkg.in/natefinch/lumberjack.v2"
 )
 
-var (
-	loggerInstance Logger
-	level          zapcore.Level
-)
+var loggerInstance Logger
 
 const (
 	TraceIdKey      = "traceID"
 	SpanIdKey       = "spanID"
+	TraceFlagsKey   = "traceFlags"
 	SpanParentIdKey = "spanParentID"
 	CallerFileKey   = "callerFile"
 	CallerFuncKey   = "callerFunc"
@@ -31,15 +28,24 @@ const (
 type Logger interface {
 	GetLevel() zapcore.Level
 	GetZapLogger() *zap.Logger
+	With(fields ...zap.Field) Logger
+	Named(name string) Logger
+	Debug(ctx context.Context, msg string, args ...zap.Field)
 	Info(ctx context.Context, msg string, args ...zap.Field)
 	Warn(ctx context.Context, msg string, args ...zap.Field)
 	Error(ctx context.Context, err error, args ...zap.Field)
 	Fatal(ctx context.Context, err error, args ...zap.Field)
 	Panic(ctx context.Context, err error, args ...zap.Field)
+	Debugw(ctx context.Context, msg string, keysAndValues ...interface{})
+	Infow(ctx context.Context, msg string, keysAndValues ...interface{})
+	Warnw(ctx context.Context, msg string, keysAndValues ...interface{})
+	Errorw(ctx context.Context, err error, keysAndValues ...interface{})
 }
 
 type logger struct {
 	log *zap.Logger
+	// skip is the number of wrapper frames between the caller and the logger methods
+	skip int
 }
 
 func NewLogger(log *zap.Logger) Logger {
@@ -47,42 +53,46 @@ func NewLogger(log *zap.Logger) Logger {
 }
 
 func Init() {
-	var location string
-	if config.Config.Log.Location != nil {
-		location = *config.Config.Log.Location
-	} else {
-		location = os.TempDir() + "/logs/" + "app.log"
-	}
+	// Set log level
+	setConfiguredLevel(configuredLevel(config.Config))
 
-	os.MkdirAll(location, os.ModePerm)
+	// Follow log level changes when the config file is reloaded
+	config.OnChange(func(conf *model.Config) {
+		setConfiguredLevel(configuredLevel(conf))
+	})
 
-	// Set retention policy for logs
-	fileWriter := &lumberjack.Logger{
-		Filename:   location,
-		MaxSize:    config.Config.Log.MaxSize,
-		MaxAge:     config.Config.Log.MaxAge,
-		MaxBackups: config.Config.Log.MaxBackups,
-		Compress:   config.Config.Log.Compress,
+	// Write to every configured sink
+	core, err := newSinksCore(config.Config)
+	if err != nil {
+		utility.PrintPanic(fmt.Sprintf("Error building logger: %s\n", err))
+		panic(err)
 	}
 
-	// Build log configuration
-	zapConfig := zap.NewProductionEncoderConfig()
+	// Tee to the cores added with AddCore, inside the sampling so exporters get the same entries as the sinks
+	core = &hookCore{Core: core}
 
-	// Set log level
-	err := level.UnmarshalText([]byte(config.Config.Log.Level))
+	// Protect the sinks from noisy loggers
+	core, err = newSamplingCore(core, config.Config)
 	if err != nil {
-		level = zapcore.InfoLevel
+		utility.PrintPanic(fmt.Sprintf("Error building logger: %s\n", err))
+		panic(err)
 	}
+	core = newDedupCore(core, config.Config)
 
-	core := zapcore.NewCore(
-		zapcore.NewJSONEncoder(zapConfig),
-		zapcore.AddSync(fileWriter),
-		level,
-	)
+	// Create logger, entries are filtered by the level of their logger name
+	zapLogger := zap.New(&overrideCore{Core: core})
 
-	// Create logger
-	logger := zap.New(core)
-	loggerInstance = NewLogger(logger)
+	// Package functions wrap the instance methods, skip them when resolving the caller
+	loggerInstance = &logger{log: zapLogger, skip: 1}
+}
+
+// configuredLevel reads log.level, defaulting to info
+func configuredLevel(conf *model.Config) zapcore.Level {
+	var lvl zapcore.Level
+	if err := lvl.UnmarshalText([]byte(conf.Log.Level)); err != nil {
+		return zapcore.InfoLevel
+	}
+	return lvl
 }
 
 func addTraceEntries(ctx context.Context, logger *zap.Logger) *zap.Logger {
@@ -90,12 +100,13 @@ func addTraceEntries(ctx context.Context, logger *zap.Logger) *zap.Logger {
 	newLogger := logger.With(
 		zap.String(TraceIdKey, sc.TraceID().String()),
 		zap.String(SpanIdKey, sc.SpanID().String()),
+		zap.String(TraceFlagsKey, sc.TraceFlags().String()),
 	)
 	return newLogger
 }
 
-func addCallerEntries(logger *zap.Logger) *zap.Logger {
-	if pc, file, line, ok := runtime.Caller(4); ok {
+func addCallerEntries(logger *zap.Logger, skip int) *zap.Logger {
+	if pc, file, line, ok := runtime.Caller(3 + skip); ok {
 		newLogger := logger.With(
 			zap.String(CallerFileKey, file),
 			zap.String(CallerFuncKey, runtime.FuncForPC(pc).Name()),
@@ -108,16 +119,18 @@ func addCallerEntries(logger *zap.Logger) *zap.Logger {
 }
 
 // StdEntries Return entries with trace ID entry from span context,
-// span ID entry from span context, and
-// span parent ID entry from context
-func stdEntries(ctx context.Context, logger *zap.Logger) *zap.Logger {
+// span ID entry from span context,
+// span parent ID entry from context, and
+// entries of the registered context extractors
+func stdEntries(ctx context.Context, logger *zap.Logger, skip int) *zap.Logger {
 	logger = addTraceEntries(ctx, logger)
-	logger = addCallerEntries(logger)
+	logger = addContextEntries(ctx, logger)
+	logger = addCallerEntries(logger, skip)
 	return logger
 }
 
 func (l *logger) GetLevel() zapcore.Level {
-	return level
+	return levels.level.Level()
 }
 
 func GetLevel() zapcore.Level {
@@ -132,9 +145,34 @@ func GetZapLogger() *zap.Logger {
 	return loggerInstance.GetZapLogger()
 }
 
+// With returns a child logger that adds fields to every entry
+func (l *logger) With(fields ...zap.Field) Logger {
+	return &logger{log: l.log.With(fields...)}
+}
+
+func With(fields ...zap.Field) Logger {
+	return loggerInstance.With(fields...)
+}
+
+// Named returns a child logger for a package or component, names are joined with a dot
+func (l *logger) Named(name string) Logger {
+	return &logger{log: l.log.Named(name)}
+}
+
+func Named(name string) Logger {
+	return loggerInstance.Named(name)
+}
+
+func (l *logger) Debug(ctx context.Context, msg string, args ...zap.Field) {
+	stdEntries(ctx, l.log, l.skip).Debug(msg, args...)
+}
+
+func Debug(ctx context.Context, msg string, args ...zap.Field) {
+	loggerInstance.Debug(ctx, msg, args...)
+}
+
 func (l *logger) Info(ctx context.Context, msg string, args ...zap.Field) {
-	utility.PrintInfo(fmt.Sprint(msg))
-	stdEntries(ctx, l.log).Info(msg, args...)
+	stdEntries(ctx, l.log, l.skip).Info(msg, args...)
 }
 
 func Info(ctx context.Context, msg string, args ...zap.Field) {
@@ -142,8 +180,7 @@ func Info(ctx context.Context, msg string, args ...zap.Field) {
 }
 
 func (l *logger) Warn(ctx context.Context, msg string, args ...zap.Field) {
-	utility.PrintWarning(fmt.Sprint(msg))
-	stdEntries(ctx, l.log).Warn(msg, args...)
+	stdEntries(ctx, l.log, l.skip).Warn(msg, args...)
 }
 
 func Warn(ctx context.Context, msg string, args ...zap.Field) {
@@ -156,11 +193,9 @@ func (l *logger) Error(ctx context.Context, err error, args ...zap.Field) {
 		span.RecordError(err)
 	}
 
-	utility.PrintError(err.Error())
-
 	args = append(args, zap.Error(err))
 
-	stdEntries(ctx, l.log).Error(err.Error(), args...)
+	stdEntries(ctx, l.log, l.skip).Error(err.Error(), args...)
 }
 
 func Error(ctx context.Context, err error, msg ...string) {
@@ -181,7 +216,7 @@ func (l *logger) Fatal(ctx context.Context, err error, args ...zap.Field) {
 
 	args = append(args, zap.Error(err))
 
-	stdEntries(ctx, l.log).Fatal(err.Error(), args...)
+	stdEntries(ctx, l.log, l.skip).Fatal(err.Error(), args...)
 }
 
 func Fatal(ctx context.Context, err error, msg ...string) {
@@ -200,11 +235,9 @@ func (l *logger) Panic(ctx context.Context, err error, args ...zap.Field) {
 		span.RecordError(err)
 	}
 
-	utility.PrintError(err.Error())
-
 	args = append(args, zap.Error(err))
 
-	stdEntries(ctx, l.log).Panic(err.Error(), args...)
+	stdEntries(ctx, l.log, l.skip).Panic(err.Error(), args...)
 }
 
 func Panic(ctx context.Context, err error, msg ...string) {
@@ -0,0 +1,242 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	otelLog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+var (
+	extraCoresMu sync.RWMutex
+	extraCores   []zapcore.Core
+)
+
+// AddCore attaches a core to every logger, including loggers created before the call.
+// It is used to bridge logs into exporters that are initialized after the logger.
+func AddCore(core zapcore.Core) {
+	extraCoresMu.Lock()
+	defer extraCoresMu.Unlock()
+
+	extraCores = append(extraCores, core)
+}
+
+func getExtraCores() []zapcore.Core {
+	extraCoresMu.RLock()
+	defer extraCoresMu.RUnlock()
+
+	return extraCores
+}
+
+// hookCore writes to the sinks core and to the cores added with AddCore
+type hookCore struct {
+	zapcore.Core
+	fields []zapcore.Field
+}
+
+func (c *hookCore) Enabled(lvl zapcore.Level) bool {
+	if c.Core.Enabled(lvl) {
+		return true
+	}
+	for _, core := range getExtraCores() {
+		if core.Enabled(lvl) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *hookCore) With(fields []zapcore.Field) zapcore.Core {
+	return &hookCore{
+		Core:   c.Core.With(fields),
+		fields: append(c.fields[:len(c.fields):len(c.fields)], fields...),
+	}
+}
+
+func (c *hookCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	checked = c.Core.Check(entry, checked)
+	for _, core := range getExtraCores() {
+		if core.Enabled(entry.Level) {
+			checked = core.With(c.fields).Check(entry, checked)
+		}
+	}
+	return checked
+}
+
+func (c *hookCore) Sync() error {
+	err := c.Core.Sync()
+	for _, core := range getExtraCores() {
+		if syncErr := core.Sync(); syncErr != nil && err == nil {
+			err = syncErr
+		}
+	}
+	return err
+}
+
+// otlpCore emits entries as OpenTelemetry log records correlated with the span of the entry
+type otlpCore struct {
+	logger otelLog.Logger
+	fields []zapcore.Field
+}
+
+// NewOtlpCore returns a core emitting to the OpenTelemetry logger,
+// it follows the global log level and the named logger overrides
+func NewOtlpCore(logger otelLog.Logger) zapcore.Core {
+	return &otlpCore{logger: logger}
+}
+
+func (c *otlpCore) Enabled(lvl zapcore.Level) bool {
+	return levels.Enabled(lvl)
+}
+
+func (c *otlpCore) With(fields []zapcore.Field) zapcore.Core {
+	return &otlpCore{
+		logger: c.logger,
+		fields: append(c.fields[:len(c.fields):len(c.fields)], fields...),
+	}
+}
+
+func (c *otlpCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if entry.Level < levels.levelFor(entry.LoggerName) {
+		return checked
+	}
+	return checked.AddCore(entry, c)
+}
+
+func (c *otlpCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	encoder := zapcore.NewMapObjectEncoder()
+	for _, field := range c.fields {
+		field.AddTo(encoder)
+	}
+	for _, field := range fields {
+		field.AddTo(encoder)
+	}
+
+	var record otelLog.Record
+	record.SetTimestamp(entry.Time)
+	record.SetObservedTimestamp(time.Now())
+	record.SetBody(otelLog.StringValue(entry.Message))
+	record.SetSeverity(otlpSeverity(entry.Level))
+	record.SetSeverityText(entry.Level.CapitalString())
+
+	if entry.LoggerName != "" {
+		record.AddAttributes(otelLog.String("logger.name", entry.LoggerName))
+	}
+
+	// Rebuild the span context from the trace entries added by stdEntries
+	ctx := context.Background()
+	traceId, _ := encoder.Fields[TraceIdKey].(string)
+	spanId, _ := encoder.Fields[SpanIdKey].(string)
+	traceFlags, _ := encoder.Fields[TraceFlagsKey].(string)
+	if tid, err := trace.TraceIDFromHex(traceId); err == nil {
+		sid, _ := trace.SpanIDFromHex(spanId)
+		ctx = trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    tid,
+			SpanID:     sid,
+			TraceFlags: parseTraceFlags(traceFlags),
+		}))
+	}
+	delete(encoder.Fields, TraceIdKey)
+	delete(encoder.Fields, SpanIdKey)
+	delete(encoder.Fields, TraceFlagsKey)
+
+	for key, value := range encoder.Fields {
+		record.AddAttributes(otelLog.KeyValue{Key: key, Value: otlpValue(value)})
+	}
+
+	c.logger.Emit(ctx, record)
+	return nil
+}
+
+func (c *otlpCore) Sync() error {
+	return nil
+}
+
+// parseTraceFlags reads the hex flags written by addTraceEntries, unknown flags are not sampled
+func parseTraceFlags(value string) trace.TraceFlags {
+	flags, err := strconv.ParseUint(value, 16, 8)
+	if err != nil {
+		return 0
+	}
+	return trace.TraceFlags(flags)
+}
+
+func otlpSeverity(lvl zapcore.Level) otelLog.Severity {
+	switch lvl {
+	case zapcore.DebugLevel:
+		return otelLog.SeverityDebug
+	case zapcore.InfoLevel:
+		return otelLog.SeverityInfo
+	case zapcore.WarnLevel:
+		return otelLog.SeverityWarn
+	case zapcore.ErrorLevel:
+		return otelLog.SeverityError
+	case zapcore.DPanicLevel:
+		return otelLog.SeverityError2
+	case zapcore.PanicLevel:
+		return otelLog.SeverityError3
+	case zapcore.FatalLevel:
+		return otelLog.SeverityFatal
+	default:
+		return otelLog.SeverityUndefined
+	}
+}
+
+// otlpValue converts values of the zap map encoder to log values
+func otlpValue(value interface{}) otelLog.Value {
+	switch v := value.(type) {
+	case string:
+		return otelLog.StringValue(v)
+	case bool:
+		return otelLog.BoolValue(v)
+	case int:
+		return otelLog.IntValue(v)
+	case int8:
+		return otelLog.Int64Value(int64(v))
+	case int16:
+		return otelLog.Int64Value(int64(v))
+	case int32:
+		return otelLog.Int64Value(int64(v))
+	case int64:
+		return otelLog.Int64Value(v)
+	case uint:
+		return otelLog.Int64Value(int64(v))
+	case uint8:
+		return otelLog.Int64Value(int64(v))
+	case uint16:
+		return otelLog.Int64Value(int64(v))
+	case uint32:
+		return otelLog.Int64Value(int64(v))
+	case uint64:
+		return otelLog.Int64Value(int64(v))
+	case float32:
+		return otelLog.Float64Value(float64(v))
+	case float64:
+		return otelLog.Float64Value(v)
+	case []byte:
+		return otelLog.BytesValue(v)
+	case time.Time:
+		return otelLog.StringValue(v.Format(time.RFC3339Nano))
+	case time.Duration:
+		return otelLog.StringValue(v.String())
+	case []interface{}:
+		values := make([]otelLog.Value, 0, len(v))
+		for _, item := range v {
+			values = append(values, otlpValue(item))
+		}
+		return otelLog.SliceValue(values...)
+	case map[string]interface{}:
+		values := make([]otelLog.KeyValue, 0, len(v))
+		for key, item := range v {
+			values = append(values, otelLog.KeyValue{Key: key, Value: otlpValue(item)})
+		}
+		return otelLog.MapValue(values...)
+	default:
+		return otelLog.StringValue(fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,305 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/alfin-efendy/helper-go/config"
+)
+
+const (
+	SqlModeFull          = "full"
+	SqlModeParameterized = "parameterized"
+
+	defaultMask        = "[REDACTED]"
+	defaultMaxBodySize = 64 * 1024
+)
+
+var (
+	redactorOnce     sync.Once
+	redactorInstance *Redactor
+
+	defaultRedactFields = []string{
+		"password", "newPassword", "oldPassword", "confirmPassword",
+		"token", "accessToken", "refreshToken", "access_token", "refresh_token",
+		"secret", "clientSecret", "apiKey", "authorization",
+	}
+	defaultRedactHeaders = []string{
+		"Authorization", "Cookie", "Set-Cookie", "X-Api-Key", "X-CSRF-Token",
+	}
+	defaultSkipContentTypes = []string{
+		"multipart/", "application/octet-stream", "application/pdf", "application/zip",
+		"image/", "audio/", "video/",
+	}
+
+	builtinPatterns = map[string]string{
+		"email": `[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`,
+		"card":  `\b(?:\d[ -]?){12,18}\d\b`,
+	}
+)
+
+type redactRule struct {
+	regex       *regexp.Regexp
+	replacement string
+}
+
+// Redactor masks sensitive values before request, response and SQL data is logged
+type Redactor struct {
+	names            map[string]bool
+	paths            map[string]bool
+	headers          map[string]bool
+	rules            []redactRule
+	maxBodySize      int
+	skipContentTypes []string
+	parameterizedSql bool
+	mask             string
+}
+
+// GetRedactor returns the redactor built from log.redaction
+func GetRedactor() *Redactor {
+	redactorOnce.Do(func() {
+		redactorInstance = newRedactor()
+	})
+	return redactorInstance
+}
+
+func newRedactor() *Redactor {
+	r := &Redactor{
+		names:            make(map[string]bool),
+		paths:            make(map[string]bool),
+		headers:          make(map[string]bool),
+		maxBodySize:      defaultMaxBodySize,
+		skipContentTypes: defaultSkipContentTypes,
+		parameterizedSql: true,
+		mask:             defaultMask,
+	}
+
+	fields := defaultRedactFields
+	headers := defaultRedactHeaders
+
+	if conf := config.Config.Log.Redaction; conf != nil {
+		fields = append(fields, conf.Fields...)
+		headers = append(headers, conf.Headers...)
+
+		if conf.MaxBodySize != 0 {
+			r.maxBodySize = conf.MaxBodySize
+		}
+		if conf.SkipContentTypes != nil {
+			r.skipContentTypes = conf.SkipContentTypes
+		}
+		if conf.SqlMode == SqlModeFull {
+			r.parameterizedSql = false
+		}
+		if conf.Mask != "" {
+			r.mask = conf.Mask
+		}
+
+		for _, pattern := range conf.Patterns {
+			expr := pattern.Regex
+			if expr == "" {
+				expr = builtinPatterns[pattern.Name]
+			}
+
+			regex, err := regexp.Compile(expr)
+			if err != nil || expr == "" {
+				Warn(context.Background(), fmt.Sprintf("Invalid redaction pattern %s", pattern.Name))
+				continue
+			}
+
+			replacement := pattern.Replacement
+			if replacement == "" {
+				replacement = r.mask
+			}
+
+			r.rules = append(r.rules, redactRule{regex: regex, replacement: replacement})
+		}
+	}
+
+	for _, field := range fields {
+		field = strings.ToLower(field)
+		if strings.Contains(field, ".") {
+			r.paths[field] = true
+		} else {
+			r.names[field] = true
+		}
+	}
+
+	for _, header := range headers {
+		r.headers[http.CanonicalHeaderKey(header)] = true
+	}
+
+	return r
+}
+
+// ParameterizedSql reports whether SQL is logged without bound values
+func (r *Redactor) ParameterizedSql() bool {
+	return r.parameterizedSql
+}
+
+func (r *Redactor) isSensitive(name string, path string) bool {
+	return r.names[strings.ToLower(name)] || r.paths[strings.ToLower(path)]
+}
+
+// SkipContentType reports whether bodies of the content type are not logged
+func (r *Redactor) SkipContentType(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+	for _, prefix := range r.skipContentTypes {
+		if strings.HasPrefix(contentType, strings.ToLower(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Headers returns the headers as a map with sensitive values masked
+func (r *Redactor) Headers(header http.Header) map[string]string {
+	result := make(map[string]string, len(header))
+	for name, values := range header {
+		if r.headers[http.CanonicalHeaderKey(name)] {
+			result[name] = r.mask
+			continue
+		}
+		result[name] = r.String(strings.Join(values, ", "))
+	}
+	return result
+}
+
+// Query masks sensitive query parameters
+func (r *Redactor) Query(rawQuery string) string {
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return r.String(rawQuery)
+	}
+
+	for key := range values {
+		if r.isSensitive(key, key) {
+			values[key] = []string{r.mask}
+		}
+	}
+
+	// Keep the mask readable in the encoded query
+	encoded := strings.ReplaceAll(values.Encode(), url.QueryEscape(r.mask), r.mask)
+
+	return r.String(encoded)
+}
+
+// Body masks sensitive fields of a JSON or form body, applies the regex rules and truncates it,
+// JSON bodies larger than the limit are omitted as they cannot be masked once cut
+func (r *Redactor) Body(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	if r.SkipContentType(contentType) {
+		return fmt.Sprintf("[omitted %s, %d bytes]", contentType, len(body))
+	}
+
+	var result string
+	trimmed := bytes.TrimSpace(body)
+	size := len(body)
+
+	switch {
+	case strings.Contains(contentType, "json") || bytes.HasPrefix(trimmed, []byte("{")) || bytes.HasPrefix(trimmed, []byte("[")):
+		// A cut JSON document cannot be parsed to mask its fields
+		if r.maxBodySize > 0 && size > r.maxBodySize {
+			return fmt.Sprintf("[omitted JSON, %d bytes]", size)
+		}
+		result = r.json(body)
+	case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+		result = r.Query(truncate(string(body), r.maxBodySize))
+	default:
+		result = truncate(string(body), r.maxBodySize)
+	}
+
+	result = r.String(result)
+
+	if r.maxBodySize > 0 && size > r.maxBodySize {
+		result = fmt.Sprintf("%s...[truncated %d bytes]", truncate(result, r.maxBodySize), size-r.maxBodySize)
+	}
+
+	return result
+}
+
+// truncate cuts the value to at most size bytes without splitting a UTF-8 character
+func truncate(value string, size int) string {
+	if size <= 0 || len(value) <= size {
+		return value
+	}
+
+	for size > 0 && !utf8.RuneStart(value[size]) {
+		size--
+	}
+	return value[:size]
+}
+
+// String applies the regex rules to a value
+func (r *Redactor) String(value string) string {
+	for _, rule := range r.rules {
+		value = rule.regex.ReplaceAllString(value, rule.replacement)
+	}
+	return value
+}
+
+func (r *Redactor) json(body []byte) string {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	// The raw body may hold secrets, it is never logged when it cannot be masked
+	var data interface{}
+	if err := decoder.Decode(&data); err != nil {
+		return fmt.Sprintf("[unparsable JSON, %d bytes]", len(body))
+	}
+
+	masked, err := json.Marshal(r.walk(data, ""))
+	if err != nil {
+		return fmt.Sprintf("[unparsable JSON, %d bytes]", len(body))
+	}
+
+	return string(masked)
+}
+
+// walk masks values whose key or dotted path is sensitive, array indexes are not part of the path
+func (r *Redactor) walk(data interface{}, path string) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+
+			if r.isSensitive(key, childPath) {
+				v[key] = r.mask
+				continue
+			}
+
+			v[key] = r.walk(value, childPath)
+		}
+		return v
+	case []interface{}:
+		for i, value := range v {
+			v[i] = r.walk(value, path)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// RedactBody masks a body with the configured redactor
+func RedactBody(contentType string, body []byte) string {
+	return GetRedactor().Body(contentType, body)
+}
+
+// RedactHeaders masks headers with the configured redactor
+func RedactHeaders(header http.Header) map[string]string {
+	return GetRedactor().Headers(header)
+}
@@ -0,0 +1,172 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/alfin-efendy/helper-go/config/model"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	defaultSamplingTick = time.Second
+	defaultDedupWindow  = 10 * time.Second
+)
+
+// levelFilterCore only passes the levels accepted by filter
+type levelFilterCore struct {
+	zapcore.Core
+	filter func(lvl zapcore.Level) bool
+}
+
+func (c *levelFilterCore) Enabled(lvl zapcore.Level) bool {
+	return c.filter(lvl) && c.Core.Enabled(lvl)
+}
+
+func (c *levelFilterCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelFilterCore{Core: c.Core.With(fields), filter: c.filter}
+}
+
+func (c *levelFilterCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.filter(entry.Level) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
+
+// newSamplingCore samples each level with its own rule, levels without a rule are not sampled
+func newSamplingCore(core zapcore.Core, conf *model.Config) (zapcore.Core, error) {
+	sampling := conf.Log.Sampling
+	if sampling == nil {
+		return core, nil
+	}
+
+	tick := defaultSamplingTick
+	if sampling.Tick > 0 {
+		tick = sampling.Tick
+	}
+
+	rules := make(map[zapcore.Level][2]int)
+	for lvl := zapcore.DebugLevel; lvl <= zapcore.FatalLevel; lvl++ {
+		if sampling.First > 0 {
+			rules[lvl] = [2]int{sampling.First, sampling.Thereafter}
+		}
+	}
+
+	for name, rule := range sampling.Levels {
+		var lvl zapcore.Level
+		if err := lvl.UnmarshalText([]byte(name)); err != nil {
+			return nil, fmt.Errorf("invalid sampling level %q: %w", name, err)
+		}
+
+		if rule.First > 0 {
+			rules[lvl] = [2]int{rule.First, rule.Thereafter}
+		} else {
+			delete(rules, lvl)
+		}
+	}
+
+	cores := make([]zapcore.Core, 0, len(rules)+1)
+	for lvl, rule := range rules {
+		lvl := lvl
+		filtered := &levelFilterCore{Core: core, filter: func(l zapcore.Level) bool { return l == lvl }}
+		cores = append(cores, zapcore.NewSamplerWithOptions(filtered, tick, rule[0], rule[1]))
+	}
+
+	cores = append(cores, &levelFilterCore{Core: core, filter: func(l zapcore.Level) bool {
+		_, sampled := rules[l]
+		return !sampled
+	}})
+
+	return zapcore.NewTee(cores...), nil
+}
+
+// dedupCore collapses repeated error messages within a window into a "repeated N times" summary
+type dedupCore struct {
+	zapcore.Core
+	state *dedupState
+}
+
+type dedupState struct {
+	mu     sync.Mutex
+	window time.Duration
+	seen   map[string]*dedupEntry
+}
+
+type dedupEntry struct {
+	entry zapcore.Entry
+	core  zapcore.Core
+	count int
+}
+
+func newDedupCore(core zapcore.Core, conf *model.Config) zapcore.Core {
+	dedup := conf.Log.Dedup
+	if dedup == nil {
+		return core
+	}
+
+	window := defaultDedupWindow
+	if dedup.Window > 0 {
+		window = dedup.Window
+	}
+
+	return &dedupCore{
+		Core: core,
+		state: &dedupState{
+			window: window,
+			seen:   make(map[string]*dedupEntry),
+		},
+	}
+}
+
+func (c *dedupCore) With(fields []zapcore.Field) zapcore.Core {
+	return &dedupCore{Core: c.Core.With(fields), state: c.state}
+}
+
+func (c *dedupCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if entry.Level != zapcore.ErrorLevel {
+		return c.Core.Check(entry, checked)
+	}
+
+	key := entry.LoggerName + "\x00" + entry.Message
+
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	if seen, ok := c.state.seen[key]; ok {
+		seen.count++
+		return checked
+	}
+
+	c.state.seen[key] = &dedupEntry{entry: entry, core: c.Core}
+	time.AfterFunc(c.state.window, func() {
+		c.flush(key)
+	})
+
+	return c.Core.Check(entry, checked)
+}
+
+// flush ends the window of a message and writes its summary when it was repeated
+func (c *dedupCore) flush(key string) {
+	c.state.mu.Lock()
+	seen := c.state.seen[key]
+	delete(c.state.seen, key)
+	c.state.mu.Unlock()
+
+	if seen == nil || seen.count == 0 {
+		return
+	}
+
+	entry := seen.entry
+	entry.Time = time.Now()
+	entry.Message = fmt.Sprintf("%s (repeated %d times)", entry.Message, seen.count)
+
+	if checked := seen.core.Check(entry, nil); checked != nil {
+		checked.Write(
+			zap.Int("repeated", seen.count),
+			zap.Duration("window", c.state.window),
+		)
+	}
+}
@@ -0,0 +1,144 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/alfin-efendy/helper-go/config"
+	"github.com/alfin-efendy/helper-go/config/model"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	SinkStdout = "stdout"
+	SinkStderr = "stderr"
+	SinkFile   = "file"
+	SinkNone   = "none"
+
+	EncodingJSON    = "json"
+	EncodingConsole = "console"
+)
+
+// defaultSinks keeps the JSON file and adds a readable console output
+var defaultSinks = []model.LogSink{
+	{Type: SinkFile, Encoding: EncodingJSON},
+	{Type: SinkStdout, Encoding: EncodingConsole},
+}
+
+// newSinksCore builds one core per configured sink and tees them together
+func newSinksCore(conf *model.Config) (zapcore.Core, error) {
+	sinks := conf.Log.Sinks
+	if len(sinks) == 0 {
+		sinks = defaultSinks
+	}
+
+	format, err := newEntryFormat(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	cores := make([]zapcore.Core, 0, len(sinks))
+	for _, sink := range sinks {
+		if sink.Type == SinkNone {
+			continue
+		}
+
+		writer, isTerminal, err := newSinkWriter(sink.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		encoder, err := newSinkEncoder(format, sink.Encoding, isTerminal)
+		if err != nil {
+			return nil, err
+		}
+
+		var enabler zapcore.LevelEnabler = levels
+		if sink.Level != "" {
+			var sinkLevel zapcore.Level
+			if err := sinkLevel.UnmarshalText([]byte(sink.Level)); err != nil {
+				return nil, fmt.Errorf("invalid level %q for %s log sink: %w", sink.Level, sink.Type, err)
+			}
+			enabler = sinkLevel
+		}
+
+		cores = append(cores, zapcore.NewCore(encoder, writer, enabler))
+	}
+
+	return zapcore.NewTee(cores...), nil
+}
+
+// newSinkWriter returns the writer of the sink and whether it is a terminal stream
+func newSinkWriter(sinkType string) (zapcore.WriteSyncer, bool, error) {
+	switch sinkType {
+	case SinkStdout:
+		return zapcore.Lock(os.Stdout), isTerminal(os.Stdout), nil
+	case SinkStderr:
+		return zapcore.Lock(os.Stderr), isTerminal(os.Stderr), nil
+	case SinkFile:
+		writer, err := newFileWriter()
+		if err != nil {
+			return nil, false, err
+		}
+		return zapcore.AddSync(writer), false, nil
+	default:
+		return nil, false, fmt.Errorf("log sink %s is not supported", sinkType)
+	}
+}
+
+// isTerminal reports whether the file is a character device, redirected or piped streams are not colored
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func newSinkEncoder(format *entryFormat, encoding string, isTerminal bool) (zapcore.Encoder, error) {
+	switch encoding {
+	case "", EncodingJSON:
+		return zapcore.NewJSONEncoder(format.jsonConfig()), nil
+	case EncodingConsole:
+		encoderConfig := format.consoleConfig()
+		if isTerminal {
+			encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		}
+		return zapcore.NewConsoleEncoder(encoderConfig), nil
+	default:
+		return nil, fmt.Errorf("log encoding %s is not supported", encoding)
+	}
+}
+
+// newFileWriter creates the parent directory of the log file and fails when the file is not writable
+func newFileWriter() (*lumberjack.Logger, error) {
+	conf := config.Config.Log
+
+	var location string
+	if conf.Location != nil {
+		location = *conf.Location
+	} else {
+		location = filepath.Join(os.TempDir(), "logs", "app.log")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(location), os.ModePerm); err != nil {
+		return nil, fmt.Errorf("failed to create log directory of %s: %w", location, err)
+	}
+
+	file, err := os.OpenFile(location, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("log location %s is not writable: %w", location, err)
+	}
+	file.Close()
+
+	// Set retention policy for logs
+	return &lumberjack.Logger{
+		Filename:   location,
+		MaxSize:    conf.MaxSize,
+		MaxAge:     conf.MaxAge,
+		MaxBackups: conf.MaxBackups,
+		Compress:   conf.Compress,
+	}, nil
+}
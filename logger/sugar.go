@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Debugw logs a message with loosely typed key-value pairs
+func (l *logger) Debugw(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	stdEntries(ctx, l.log, l.skip).Sugar().Debugw(msg, keysAndValues...)
+}
+
+func Debugw(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	loggerInstance.Debugw(ctx, msg, keysAndValues...)
+}
+
+// Infow logs a message with loosely typed key-value pairs
+func (l *logger) Infow(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	stdEntries(ctx, l.log, l.skip).Sugar().Infow(msg, keysAndValues...)
+}
+
+func Infow(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	loggerInstance.Infow(ctx, msg, keysAndValues...)
+}
+
+// Warnw logs a message with loosely typed key-value pairs
+func (l *logger) Warnw(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	stdEntries(ctx, l.log, l.skip).Sugar().Warnw(msg, keysAndValues...)
+}
+
+func Warnw(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	loggerInstance.Warnw(ctx, msg, keysAndValues...)
+}
+
+// Errorw logs the error with loosely typed key-value pairs and records it on the span
+func (l *logger) Errorw(ctx context.Context, err error, keysAndValues ...interface{}) {
+	span := trace.SpanFromContext(ctx)
+	if span != nil {
+		span.RecordError(err)
+	}
+
+	keysAndValues = append(keysAndValues, "error", err)
+
+	stdEntries(ctx, l.log, l.skip).Sugar().Errorw(err.Error(), keysAndValues...)
+}
+
+func Errorw(ctx context.Context, err error, keysAndValues ...interface{}) {
+	loggerInstance.Errorw(ctx, err, keysAndValues...)
+}
@@ -0,0 +1,231 @@
+package otel
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/alfin-efendy/helper-go/config/model"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	ExporterOtlp   = "otlp"
+	ExporterStdout = "stdout"
+
+	ProtocolGrpc = "grpc"
+	ProtocolHttp = "http"
+)
+
+// usesGrpc reports whether the exporters share a gRPC connection to the collector
+func usesGrpc(conf model.Otel) bool {
+	return exporterOf(conf) == ExporterOtlp && protocolOf(conf) == ProtocolGrpc
+}
+
+// hasCollector reports whether the exporters have somewhere to push, the OTLP exporters need otel.host
+func hasCollector(conf model.Otel) bool {
+	return exporterOf(conf) == ExporterStdout || conf.Host != ""
+}
+
+func exporterOf(conf model.Otel) string {
+	if conf.Exporter == "" {
+		return ExporterOtlp
+	}
+	return conf.Exporter
+}
+
+func protocolOf(conf model.Otel) string {
+	if conf.Protocol == "" {
+		return ProtocolGrpc
+	}
+	return conf.Protocol
+}
+
+func initGrpcConn(ctx context.Context, conf model.Otel) (*grpc.ClientConn, error) {
+	creds := insecure.NewCredentials()
+	if conf.TLS != nil {
+		tlsConfig, err := newTLSConfig(conf.TLS)
+		if err != nil {
+			return nil, err
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	return grpc.NewClient(conf.Host, grpc.WithTransportCredentials(creds))
+}
+
+// newTLSConfig loads the CA and client certificate of the collector connection
+func newTLSConfig(conf *model.OtelTLS) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         conf.ServerName,
+		InsecureSkipVerify: conf.InsecureSkipVerify,
+	}
+
+	if conf.CAFile != "" {
+		ca, err := os.ReadFile(conf.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read otel CA file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("otel CA file %s has no certificate", conf.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if conf.CertFile != "" || conf.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(conf.CertFile, conf.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load otel client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// hasScheme reports whether the host is a full URL instead of host and port
+func hasScheme(host string) bool {
+	return strings.Contains(host, "://")
+}
+
+func newTraceExporter(ctx context.Context, conf model.Otel, conn *grpc.ClientConn) (sdktrace.SpanExporter, error) {
+	switch exporterOf(conf) {
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case ExporterOtlp:
+	default:
+		return nil, fmt.Errorf("otel exporter %s is not supported", conf.Exporter)
+	}
+
+	switch protocolOf(conf) {
+	case ProtocolGrpc:
+		return otlptracegrpc.New(ctx,
+			otlptracegrpc.WithGRPCConn(conn),
+			otlptracegrpc.WithTimeout(conf.Timeout),
+			otlptracegrpc.WithHeaders(conf.Headers),
+		)
+	case ProtocolHttp:
+		opts := []otlptracehttp.Option{
+			otlptracehttp.WithTimeout(conf.Timeout),
+			otlptracehttp.WithHeaders(conf.Headers),
+		}
+		if hasScheme(conf.Host) {
+			opts = append(opts, otlptracehttp.WithEndpointURL(conf.Host))
+		} else {
+			opts = append(opts, otlptracehttp.WithEndpoint(conf.Host))
+		}
+		if conf.TLS == nil {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		} else {
+			tlsConfig, err := newTLSConfig(conf.TLS)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, otlptracehttp.WithTLSClientConfig(tlsConfig))
+		}
+		return otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("otel protocol %s is not supported", conf.Protocol)
+	}
+}
+
+func newMetricExporter(ctx context.Context, conf model.Otel, conn *grpc.ClientConn) (sdkmetric.Exporter, error) {
+	switch exporterOf(conf) {
+	case ExporterStdout:
+		return stdoutmetric.New(stdoutmetric.WithPrettyPrint())
+	case ExporterOtlp:
+	default:
+		return nil, fmt.Errorf("otel exporter %s is not supported", conf.Exporter)
+	}
+
+	switch protocolOf(conf) {
+	case ProtocolGrpc:
+		return otlpmetricgrpc.New(ctx,
+			otlpmetricgrpc.WithGRPCConn(conn),
+			otlpmetricgrpc.WithTimeout(conf.Timeout),
+			otlpmetricgrpc.WithHeaders(conf.Headers),
+		)
+	case ProtocolHttp:
+		opts := []otlpmetrichttp.Option{
+			otlpmetrichttp.WithTimeout(conf.Timeout),
+			otlpmetrichttp.WithHeaders(conf.Headers),
+		}
+		if hasScheme(conf.Host) {
+			opts = append(opts, otlpmetrichttp.WithEndpointURL(conf.Host))
+		} else {
+			opts = append(opts, otlpmetrichttp.WithEndpoint(conf.Host))
+		}
+		if conf.TLS == nil {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		} else {
+			tlsConfig, err := newTLSConfig(conf.TLS)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, otlpmetrichttp.WithTLSClientConfig(tlsConfig))
+		}
+		return otlpmetrichttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("otel protocol %s is not supported", conf.Protocol)
+	}
+}
+
+func newLogExporter(ctx context.Context, conf model.Otel, conn *grpc.ClientConn) (sdklog.Exporter, error) {
+	switch exporterOf(conf) {
+	case ExporterStdout:
+		return stdoutlog.New(stdoutlog.WithPrettyPrint())
+	case ExporterOtlp:
+	default:
+		return nil, fmt.Errorf("otel exporter %s is not supported", conf.Exporter)
+	}
+
+	switch protocolOf(conf) {
+	case ProtocolGrpc:
+		return otlploggrpc.New(ctx,
+			otlploggrpc.WithGRPCConn(conn),
+			otlploggrpc.WithTimeout(conf.Timeout),
+			otlploggrpc.WithHeaders(conf.Headers),
+		)
+	case ProtocolHttp:
+		opts := []otlploghttp.Option{
+			otlploghttp.WithTimeout(conf.Timeout),
+			otlploghttp.WithHeaders(conf.Headers),
+		}
+		if hasScheme(conf.Host) {
+			opts = append(opts, otlploghttp.WithEndpointURL(conf.Host))
+		} else {
+			opts = append(opts, otlploghttp.WithEndpoint(conf.Host))
+		}
+		if conf.TLS == nil {
+			opts = append(opts, otlploghttp.WithInsecure())
+		} else {
+			tlsConfig, err := newTLSConfig(conf.TLS)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, otlploghttp.WithTLSClientConfig(tlsConfig))
+		}
+		return otlploghttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("otel protocol %s is not supported", conf.Protocol)
+	}
+}
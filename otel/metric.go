@@ -0,0 +1,261 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/alfin-efendy/helper-go/logger"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var ErrInstrumentExists = errors.New("instrument is already registered")
+
+func (o *otelWrapper) AddCounter(_ context.Context, counterName string, unit string) error {
+	_, err := o.counter(counterName, unit)
+	return err
+}
+
+// counter returns the registered counter or registers it
+func (o *otelWrapper) counter(counterName string, unit string) (metric.Int64Counter, error) {
+	o.mu.RLock()
+	counter, ok := o.counters[counterName]
+	o.mu.RUnlock()
+	if ok {
+		return counter, nil
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if counter, ok := o.counters[counterName]; ok {
+		return counter, nil
+	}
+
+	counter, err := o.meter.Int64Counter(counterName, metric.WithUnit(unit))
+	if err != nil {
+		return nil, err
+	}
+
+	o.counters[counterName] = counter
+	return counter, nil
+}
+
+func AddCounter(ctx context.Context, counterName string, unit string) error {
+	return otelInstance.AddCounter(ctx, counterName, unit)
+}
+
+func (o *otelWrapper) Count(ctx context.Context, counterName string, incr int64, opts ...metric.AddOption) {
+	counter, err := o.counter(counterName, "")
+	if err != nil {
+		logger.Error(ctx, err, fmt.Sprintf("Failed to register counter %s", counterName))
+		return
+	}
+	counter.Add(ctx, incr, opts...)
+}
+
+// Count increments the counter, it is registered without unit on first use
+func Count(ctx context.Context, counterName string, incr int64, opts ...metric.AddOption) {
+	otelInstance.Count(ctx, counterName, incr, opts...)
+}
+
+func (o *otelWrapper) AddUpDownCounter(_ context.Context, counterName string, unit string) error {
+	_, err := o.upDownCounter(counterName, unit)
+	return err
+}
+
+func (o *otelWrapper) upDownCounter(counterName string, unit string) (metric.Int64UpDownCounter, error) {
+	o.mu.RLock()
+	counter, ok := o.upDownCounters[counterName]
+	o.mu.RUnlock()
+	if ok {
+		return counter, nil
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if counter, ok := o.upDownCounters[counterName]; ok {
+		return counter, nil
+	}
+
+	counter, err := o.meter.Int64UpDownCounter(counterName, metric.WithUnit(unit))
+	if err != nil {
+		return nil, err
+	}
+
+	o.upDownCounters[counterName] = counter
+	return counter, nil
+}
+
+func AddUpDownCounter(ctx context.Context, counterName string, unit string) error {
+	return otelInstance.AddUpDownCounter(ctx, counterName, unit)
+}
+
+func (o *otelWrapper) UpDownCount(ctx context.Context, counterName string, incr int64, opts ...metric.AddOption) {
+	counter, err := o.upDownCounter(counterName, "")
+	if err != nil {
+		logger.Error(ctx, err, fmt.Sprintf("Failed to register up down counter %s", counterName))
+		return
+	}
+	counter.Add(ctx, incr, opts...)
+}
+
+// UpDownCount adds incr to a value that may go down, like the depth of a queue
+func UpDownCount(ctx context.Context, counterName string, incr int64, opts ...metric.AddOption) {
+	otelInstance.UpDownCount(ctx, counterName, incr, opts...)
+}
+
+func (o *otelWrapper) AddHistogram(_ context.Context, histogramName string, unit string, buckets ...float64) error {
+	_, err := o.histogram(histogramName, unit, buckets)
+	return err
+}
+
+// histogram returns the registered histogram or registers it, buckets are only used on registration
+func (o *otelWrapper) histogram(histogramName string, unit string, buckets []float64) (metric.Float64Histogram, error) {
+	o.mu.RLock()
+	histogram, ok := o.histograms[histogramName]
+	o.mu.RUnlock()
+	if ok {
+		return histogram, nil
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if histogram, ok := o.histograms[histogramName]; ok {
+		return histogram, nil
+	}
+
+	opts := []metric.Float64HistogramOption{metric.WithUnit(unit)}
+	if len(buckets) > 0 {
+		opts = append(opts, metric.WithExplicitBucketBoundaries(buckets...))
+	}
+
+	histogram, err := o.meter.Float64Histogram(histogramName, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	o.histograms[histogramName] = histogram
+	return histogram, nil
+}
+
+// AddHistogram registers a histogram, buckets are the explicit boundaries and default to the SDK ones
+func AddHistogram(ctx context.Context, histogramName string, unit string, buckets ...float64) error {
+	return otelInstance.AddHistogram(ctx, histogramName, unit, buckets...)
+}
+
+func (o *otelWrapper) Record(ctx context.Context, histogramName string, value float64, opts ...metric.RecordOption) {
+	histogram, err := o.histogram(histogramName, "", nil)
+	if err != nil {
+		logger.Error(ctx, err, fmt.Sprintf("Failed to register histogram %s", histogramName))
+		return
+	}
+	histogram.Record(ctx, value, opts...)
+}
+
+// Record adds the value to the histogram, it is registered with default buckets on first use
+func Record(ctx context.Context, histogramName string, value float64, opts ...metric.RecordOption) {
+	otelInstance.Record(ctx, histogramName, value, opts...)
+}
+
+func (o *otelWrapper) AddInt64Histogram(_ context.Context, histogramName string, unit string, buckets ...float64) error {
+	_, err := o.int64Histogram(histogramName, unit, buckets)
+	return err
+}
+
+func (o *otelWrapper) int64Histogram(histogramName string, unit string, buckets []float64) (metric.Int64Histogram, error) {
+	o.mu.RLock()
+	histogram, ok := o.int64Histograms[histogramName]
+	o.mu.RUnlock()
+	if ok {
+		return histogram, nil
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if histogram, ok := o.int64Histograms[histogramName]; ok {
+		return histogram, nil
+	}
+
+	opts := []metric.Int64HistogramOption{metric.WithUnit(unit)}
+	if len(buckets) > 0 {
+		opts = append(opts, metric.WithExplicitBucketBoundaries(buckets...))
+	}
+
+	histogram, err := o.meter.Int64Histogram(histogramName, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	o.int64Histograms[histogramName] = histogram
+	return histogram, nil
+}
+
+func AddInt64Histogram(ctx context.Context, histogramName string, unit string, buckets ...float64) error {
+	return otelInstance.AddInt64Histogram(ctx, histogramName, unit, buckets...)
+}
+
+func (o *otelWrapper) RecordInt64(ctx context.Context, histogramName string, value int64, opts ...metric.RecordOption) {
+	histogram, err := o.int64Histogram(histogramName, "", nil)
+	if err != nil {
+		logger.Error(ctx, err, fmt.Sprintf("Failed to register histogram %s", histogramName))
+		return
+	}
+	histogram.Record(ctx, value, opts...)
+}
+
+func RecordInt64(ctx context.Context, histogramName string, value int64, opts ...metric.RecordOption) {
+	otelInstance.RecordInt64(ctx, histogramName, value, opts...)
+}
+
+func (o *otelWrapper) AddGauge(_ context.Context, gaugeName string, unit string, callback metric.Float64Callback) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, ok := o.gauges[gaugeName]; ok {
+		return fmt.Errorf("%w: %s", ErrInstrumentExists, gaugeName)
+	}
+
+	gauge, err := o.meter.Float64ObservableGauge(gaugeName,
+		metric.WithUnit(unit),
+		metric.WithFloat64Callback(callback),
+	)
+	if err != nil {
+		return err
+	}
+
+	o.gauges[gaugeName] = gauge
+	return nil
+}
+
+// AddGauge registers a gauge whose value is read by the callback on every collection
+func AddGauge(ctx context.Context, gaugeName string, unit string, callback metric.Float64Callback) error {
+	return otelInstance.AddGauge(ctx, gaugeName, unit, callback)
+}
+
+func (o *otelWrapper) AddInt64Gauge(_ context.Context, gaugeName string, unit string, callback metric.Int64Callback) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, ok := o.gauges[gaugeName]; ok {
+		return fmt.Errorf("%w: %s", ErrInstrumentExists, gaugeName)
+	}
+
+	gauge, err := o.meter.Int64ObservableGauge(gaugeName,
+		metric.WithUnit(unit),
+		metric.WithInt64Callback(callback),
+	)
+	if err != nil {
+		return err
+	}
+
+	o.gauges[gaugeName] = gauge
+	return nil
+}
+
+func AddInt64Gauge(ctx context.Context, gaugeName string, unit string, callback metric.Int64Callback) error {
+	return otelInstance.AddInt64Gauge(ctx, gaugeName, unit, callback)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/alfin-efendy/helper-go/config"
@@ -12,25 +13,33 @@ import (
 	"github.com/alfin-efendy/helper-go/logger"
 	"github.com/alfin-efendy/helper-go/utility"
 	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/log/global"
 	"go.opentelemetry.io/otel/metric"
+	metricNoop "go.opentelemetry.io/otel/metric/noop"
 	"go.opentelemetry.io/otel/propagation"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"go.opentelemetry.io/otel/trace"
+	traceNoop "go.opentelemetry.io/otel/trace/noop"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
+const defaultMetricInterval = 3 * time.Second
+
 var (
-	otelInstance Otel
-	configs      *model.Config
-	isEnabled    bool
-	serviceName  string
-	Shutdown     = func(context.Context) error {
+	// otelInstance does nothing until Init enables telemetry, so tracing is always safe to call
+	otelInstance = NewOtel(
+		traceNoop.NewTracerProvider().Tracer(""),
+		metricNoop.NewMeterProvider().Meter(""),
+		make(map[string]metric.Int64Counter),
+	)
+	configs     *model.Config
+	isEnabled   bool
+	serviceName string
+	Shutdown    = func(context.Context) error {
 		return nil
 	}
 )
@@ -39,6 +48,14 @@ type Otel interface {
 	Trace(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, *SpanWrapper)
 	AddCounter(ctx context.Context, counterName string, unit string) error
 	Count(ctx context.Context, counterName string, incr int64, opts ...metric.AddOption)
+	AddUpDownCounter(ctx context.Context, counterName string, unit string) error
+	UpDownCount(ctx context.Context, counterName string, incr int64, opts ...metric.AddOption)
+	AddHistogram(ctx context.Context, histogramName string, unit string, buckets ...float64) error
+	Record(ctx context.Context, histogramName string, value float64, opts ...metric.RecordOption)
+	AddInt64Histogram(ctx context.Context, histogramName string, unit string, buckets ...float64) error
+	RecordInt64(ctx context.Context, histogramName string, value int64, opts ...metric.RecordOption)
+	AddGauge(ctx context.Context, gaugeName string, unit string, callback metric.Float64Callback) error
+	AddInt64Gauge(ctx context.Context, gaugeName string, unit string, callback metric.Int64Callback) error
 }
 
 type SpanWrapper struct {
@@ -46,16 +63,31 @@ type SpanWrapper struct {
 }
 
 type otelWrapper struct {
-	tracer   trace.Tracer
-	meter    metric.Meter
-	counters map[string]metric.Int64Counter
+	tracer trace.Tracer
+	meter  metric.Meter
+
+	// mu guards the instruments, they are registered on first use
+	mu              sync.RWMutex
+	counters        map[string]metric.Int64Counter
+	upDownCounters  map[string]metric.Int64UpDownCounter
+	histograms      map[string]metric.Float64Histogram
+	int64Histograms map[string]metric.Int64Histogram
+	gauges          map[string]metric.Observable
 }
 
 func NewOtel(tracer trace.Tracer, meter metric.Meter, counters map[string]metric.Int64Counter) Otel {
+	if counters == nil {
+		counters = make(map[string]metric.Int64Counter)
+	}
+
 	return &otelWrapper{
-		tracer:   tracer,
-		meter:    meter,
-		counters: counters,
+		tracer:          tracer,
+		meter:           meter,
+		counters:        counters,
+		upDownCounters:  make(map[string]metric.Int64UpDownCounter),
+		histograms:      make(map[string]metric.Float64Histogram),
+		int64Histograms: make(map[string]metric.Int64Histogram),
+		gauges:          make(map[string]metric.Observable),
 	}
 }
 
@@ -64,8 +96,14 @@ func Init() {
 	configs = config.Config
 
 	// Check if OpenTelemetry is enabled
-	if !configs.Otel.Trace && !configs.Otel.Metric {
+	if !configs.Otel.Trace && !configs.Otel.Metric && !configs.Otel.Log {
 		isEnabled = false
+		serviceName = configs.App.Name
+		otelInstance = NewOtel(
+			traceNoop.NewTracerProvider().Tracer(serviceName),
+			metricNoop.NewMeterProvider().Meter(serviceName),
+			make(map[string]metric.Int64Counter),
+		)
 		logger.Warn(ctx, "OpenTelemetry is disabled")
 		return
 	}
@@ -79,27 +117,18 @@ func Init() {
 	)
 
 	serviceName = configs.App.Name
-	res, err := resource.New(ctx,
-		resource.WithFromEnv(),
-		resource.WithProcess(),
-		resource.WithTelemetrySDK(),
-		resource.WithHost(),
-		resource.WithAttributes(
-			// the service name used to display traces in backends
-			semconv.ServiceNameKey.String(serviceName),
-		),
-	)
+	res, err := newResource(ctx, configs)
 	if err != nil {
 		logger.Fatal(ctx, err, "Failed to create resource")
 		return
 	}
 
-	// Initialize grpc connection
-	conn, err := initGrpcConn(ctx, configs.Otel.Host)
+	conf := configs.Otel
 
-	// Intialize shutdown hook
+	// Intialize shutdown hook, providers are flushed before the connection is closed
 	var shutdownHooks []func(context.Context) error
 	Shutdown = func(ctx context.Context) error {
+		var err error
 		for _, hook := range shutdownHooks {
 			err = errors.Join(err, hook(ctx))
 		}
@@ -107,22 +136,52 @@ func Init() {
 		return err
 	}
 
-	if configs.Otel.Trace {
+	// Initialize grpc connection shared by the exporters
+	var conn *grpc.ClientConn
+	if usesGrpc(conf) && hasCollector(conf) {
+		conn, err = initGrpcConn(ctx, conf)
+		if err != nil {
+			logger.Fatal(ctx, err, "Failed to create gRPC connection")
+			return
+		}
+	}
+
+	if conf.Trace {
 		// Initialize trace provider
-		err = initTracerProvider(ctx, res, conn)
+		shutdown, err := initTracerProvider(ctx, res, conn)
 		if err != nil {
 			logger.Fatal(ctx, err, "Failed to initialize OpenTelemetry trace provider")
 			return
 		}
+		shutdownHooks = append(shutdownHooks, shutdown)
 	}
 
-	if configs.Otel.Metric {
+	if conf.Metric {
 		// Initialize metric provider
-		err = initMetricProvider(ctx, res, conn)
+		shutdown, err := initMetricProvider(ctx, res, conn)
 		if err != nil {
 			logger.Fatal(ctx, err, "Failed to initialize OpenTelemetry metric provider")
 			return
 		}
+		shutdownHooks = append(shutdownHooks, shutdown)
+	}
+
+	if conf.Log && !hasCollector(conf) {
+		logger.Warn(ctx, "OpenTelemetry logs are not exported, otel.host is not set")
+	} else if conf.Log {
+		// Initialize logger provider
+		shutdown, err := initLoggerProvider(ctx, res, conn)
+		if err != nil {
+			logger.Fatal(ctx, err, "Failed to initialize OpenTelemetry logger provider")
+			return
+		}
+		shutdownHooks = append(shutdownHooks, shutdown)
+	}
+
+	if conn != nil {
+		shutdownHooks = append(shutdownHooks, func(context.Context) error {
+			return conn.Close()
+		})
 	}
 
 	// Set default tracer
@@ -131,73 +190,167 @@ func Init() {
 	// Set default meter
 	meter := otel.Meter(serviceName)
 
+	if conf.Metric && conf.Runtime {
+		if err := initRuntimeMetrics(meter); err != nil {
+			logger.Fatal(ctx, err, "Failed to register runtime metrics")
+			return
+		}
+	}
+
 	// Init default counters
 	counters := make(map[string]metric.Int64Counter)
 	otelInstance = NewOtel(tracer, meter, counters)
+	isEnabled = true
 }
 
-func initGrpcConn(ctx context.Context, address string) (*grpc.ClientConn, error) {
-	conn, err := grpc.NewClient(
-		address,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
-	if err != nil {
-		logger.Fatal(ctx, err, "Failed to create gRPC connection")
-		return nil, err
-	}
-
-	return conn, nil
-}
-
-func initTracerProvider(ctx context.Context, res *resource.Resource, conn *grpc.ClientConn) error {
+func initTracerProvider(ctx context.Context, res *resource.Resource, conn *grpc.ClientConn) (func(context.Context) error, error) {
 	conf := configs.Otel
 
-	exporter, err := otlptracegrpc.New(
-		ctx,
-		otlptracegrpc.WithGRPCConn(conn),
-		otlptracegrpc.WithTimeout(time.Duration(conf.Timeout)*time.Second),
-	)
+	// Spans still carry the trace id of the logs and outgoing requests without a collector
+	if !hasCollector(conf) {
+		logger.Warn(ctx, "OpenTelemetry spans are not exported, otel.host is not set")
+		tracerProvider := sdktrace.NewTracerProvider(
+			sdktrace.WithResource(res),
+			sdktrace.WithSampler(newSampler(conf)),
+		)
+		otel.SetTracerProvider(tracerProvider)
+		return tracerProvider.Shutdown, nil
+	}
+
+	exporter, err := newTraceExporter(ctx, conf, conn)
 	if err != nil {
 		logger.Fatal(ctx, err, "Failed to create trace exporter")
-		return err
+		return nil, err
+	}
+
+	var batchOpts []sdktrace.BatchSpanProcessorOption
+	if batch := conf.Batch; batch != nil {
+		if batch.MaxQueueSize > 0 {
+			batchOpts = append(batchOpts, sdktrace.WithMaxQueueSize(batch.MaxQueueSize))
+		}
+		if batch.MaxExportBatchSize > 0 {
+			batchOpts = append(batchOpts, sdktrace.WithMaxExportBatchSize(batch.MaxExportBatchSize))
+		}
+		if batch.BatchTimeout > 0 {
+			batchOpts = append(batchOpts, sdktrace.WithBatchTimeout(batch.BatchTimeout))
+		}
+		if batch.ExportTimeout > 0 {
+			batchOpts = append(batchOpts, sdktrace.WithExportTimeout(batch.ExportTimeout))
+		}
 	}
 
 	tracerProvider := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(exporter),
+		sdktrace.WithBatcher(exporter, batchOpts...),
 		sdktrace.WithResource(res),
-		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithSampler(newSampler(conf)),
 	)
 
 	otel.SetTracerProvider(tracerProvider)
 
-	return nil
+	return tracerProvider.Shutdown, nil
 }
 
-func initMetricProvider(ctx context.Context, res *resource.Resource, conn *grpc.ClientConn) error {
-	conf := configs.Otel
+// newSampler samples every trace unless a ratio is configured, by default it follows the parent decision
+func newSampler(conf model.Otel) sdktrace.Sampler {
+	if conf.Sampler == nil {
+		return sdktrace.AlwaysSample()
+	}
 
-	exporter, err := otlpmetricgrpc.New(
-		ctx,
-		otlpmetricgrpc.WithGRPCConn(conn),
-		otlpmetricgrpc.WithTimeout(time.Duration(conf.Timeout)*time.Second),
-	)
-	if err != nil {
-		logger.Fatal(ctx, err, "Failed to create metric exporter")
-		return err
+	sampler := sdktrace.TraceIDRatioBased(conf.Sampler.Ratio)
+	if conf.Sampler.ParentBased != nil && !*conf.Sampler.ParentBased {
+		return sampler
 	}
 
-	meterProvider := sdkmetric.NewMeterProvider(
-		sdkmetric.WithReader(
+	return sdktrace.ParentBased(sampler)
+}
+
+func initMetricProvider(ctx context.Context, res *resource.Resource, conn *grpc.ClientConn) (func(context.Context) error, error) {
+	conf := configs.Otel
+
+	opts := []sdkmetric.Option{sdkmetric.WithResource(res)}
+
+	// Push to the collector, clusters scraping prometheus may run without one
+	if !hasCollector(conf) && conf.Prometheus == nil {
+		logger.Warn(ctx, "OpenTelemetry metrics are not exported, otel.host and otel.prometheus are not set")
+	} else if hasCollector(conf) {
+		exporter, err := newMetricExporter(ctx, conf, conn)
+		if err != nil {
+			logger.Fatal(ctx, err, "Failed to create metric exporter")
+			return nil, err
+		}
+
+		interval := defaultMetricInterval
+		if conf.MetricInterval > 0 {
+			interval = conf.MetricInterval
+		}
+
+		opts = append(opts, sdkmetric.WithReader(
 			sdkmetric.NewPeriodicReader(exporter,
-				sdkmetric.WithInterval(3*time.Second),
+				sdkmetric.WithInterval(interval),
 			),
-		),
-		sdkmetric.WithResource(res),
-	)
+		))
+	}
+
+	shutdown := func(context.Context) error { return nil }
+	if conf.Prometheus != nil {
+		reader, handler, err := newPrometheusReader()
+		if err != nil {
+			logger.Fatal(ctx, err, "Failed to create prometheus reader")
+			return nil, err
+		}
+		opts = append(opts, sdkmetric.WithReader(reader))
+		metricsHandler = handler
+
+		if conf.Prometheus.Port > 0 {
+			shutdown = servePrometheus(ctx, PrometheusPath(), conf.Prometheus.Port, handler)
+		}
+	}
+
+	meterProvider := sdkmetric.NewMeterProvider(opts...)
 
 	otel.SetMeterProvider(meterProvider)
 
-	return nil
+	return func(ctx context.Context) error {
+		return errors.Join(shutdown(ctx), meterProvider.Shutdown(ctx))
+	}, nil
+}
+
+func initLoggerProvider(ctx context.Context, res *resource.Resource, conn *grpc.ClientConn) (func(context.Context) error, error) {
+	conf := configs.Otel
+
+	exporter, err := newLogExporter(ctx, conf, conn)
+	if err != nil {
+		logger.Fatal(ctx, err, "Failed to create log exporter")
+		return nil, err
+	}
+
+	var batchOpts []sdklog.BatchProcessorOption
+	if batch := conf.Batch; batch != nil {
+		if batch.MaxQueueSize > 0 {
+			batchOpts = append(batchOpts, sdklog.WithMaxQueueSize(batch.MaxQueueSize))
+		}
+		if batch.MaxExportBatchSize > 0 {
+			batchOpts = append(batchOpts, sdklog.WithExportMaxBatchSize(batch.MaxExportBatchSize))
+		}
+		if batch.BatchTimeout > 0 {
+			batchOpts = append(batchOpts, sdklog.WithExportInterval(batch.BatchTimeout))
+		}
+		if batch.ExportTimeout > 0 {
+			batchOpts = append(batchOpts, sdklog.WithExportTimeout(batch.ExportTimeout))
+		}
+	}
+
+	loggerProvider := sdklog.NewLoggerProvider(
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter, batchOpts...)),
+		sdklog.WithResource(res),
+	)
+
+	global.SetLoggerProvider(loggerProvider)
+
+	// Bridge the application logs, the trace and span id of each entry correlate it with its span
+	logger.AddCore(logger.NewOtlpCore(loggerProvider.Logger(serviceName)))
+
+	return loggerProvider.Shutdown, nil
 }
 
 func (o *otelWrapper) Trace(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, *SpanWrapper) {
@@ -234,25 +387,3 @@ func Trace(ctx context.Context, opts ...trace.SpanStartOption) (context.Context,
 func (w *SpanWrapper) End(options ...trace.SpanEndOption) {
 	w.span.End(options...)
 }
-
-func (o *otelWrapper) AddCounter(_ context.Context, counterName string, unit string) error {
-	counter, err := o.meter.Int64Counter(counterName, metric.WithUnit(unit))
-	if err != nil {
-		return err
-	}
-
-	o.counters[counterName] = counter
-	return nil
-}
-
-func AddCounter(ctx context.Context, counterName string, unit string) error {
-	return otelInstance.AddCounter(ctx, counterName, unit)
-}
-
-func (o *otelWrapper) Count(ctx context.Context, counterName string, incr int64, opts ...metric.AddOption) {
-	o.counters[counterName].Add(ctx, incr, opts...)
-}
-
-func Count(ctx context.Context, counterName string, incr int64, opts ...metric.AddOption) {
-	otelInstance.Count(ctx, counterName, incr, opts...)
-}
@@ -0,0 +1,70 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/alfin-efendy/helper-go/logger"
+	promClient "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+const defaultPrometheusPath = "/metrics"
+
+var metricsHandler http.Handler
+
+// newPrometheusReader returns a reader collecting the metrics on every scrape of the handler
+func newPrometheusReader() (sdkmetric.Reader, http.Handler, error) {
+	registry := promClient.NewRegistry()
+
+	reader, err := prometheus.New(prometheus.WithRegisterer(registry))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return reader, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}), nil
+}
+
+// servePrometheus serves the handler on its own port and returns the shutdown of the server
+func servePrometheus(ctx context.Context, path string, port int, handler http.Handler) func(context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle(path, handler)
+
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%d", port),
+		Handler: mux,
+	}
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error(ctx, err, fmt.Sprintf("Failed to serve prometheus metrics, port=%d", port))
+		}
+	}()
+
+	return server.Shutdown
+}
+
+// PrometheusPath returns the scrape path, it is empty when prometheus is not enabled
+func PrometheusPath() string {
+	if metricsHandler == nil {
+		return ""
+	}
+
+	if path := configs.Otel.Prometheus.Path; path != "" {
+		return path
+	}
+	return defaultPrometheusPath
+}
+
+// MetricsHandler returns the scrape handler to mount on the REST API server,
+// it is nil when prometheus is not enabled or has its own port
+func MetricsHandler() http.Handler {
+	if metricsHandler == nil || configs.Otel.Prometheus.Port > 0 {
+		return nil
+	}
+	return metricsHandler
+}
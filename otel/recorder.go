@@ -0,0 +1,87 @@
+package otel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// Recorder keeps spans and metrics in memory so tests can assert on them
+type Recorder struct {
+	spans  *tracetest.SpanRecorder
+	reader *sdkmetric.ManualReader
+}
+
+// UseRecorder replaces the providers with in-memory ones, it does not need a collector
+func UseRecorder(name string) *Recorder {
+	recorder := &Recorder{
+		spans:  tracetest.NewSpanRecorder(),
+		reader: sdkmetric.NewManualReader(),
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(recorder.spans),
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+	)
+	meterProvider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(recorder.reader),
+	)
+
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetMeterProvider(meterProvider)
+
+	serviceName = name
+	otelInstance = NewOtel(
+		tracerProvider.Tracer(name),
+		meterProvider.Meter(name),
+		make(map[string]metric.Int64Counter),
+	)
+	isEnabled = true
+
+	return recorder
+}
+
+// Spans returns the spans ended since the recorder was installed
+func (r *Recorder) Spans() []sdktrace.ReadOnlySpan {
+	return r.spans.Ended()
+}
+
+// Span returns the last ended span with the name
+func (r *Recorder) Span(name string) (sdktrace.ReadOnlySpan, bool) {
+	spans := r.spans.Ended()
+	for i := len(spans) - 1; i >= 0; i-- {
+		if spans[i].Name() == name {
+			return spans[i], true
+		}
+	}
+	return nil, false
+}
+
+// Metrics collects the current value of every instrument
+func (r *Recorder) Metrics(ctx context.Context) (metricdata.ResourceMetrics, error) {
+	var data metricdata.ResourceMetrics
+	err := r.reader.Collect(ctx, &data)
+	return data, err
+}
+
+// Metric returns the collected data of the instrument with the name
+func (r *Recorder) Metric(ctx context.Context, name string) (metricdata.Metrics, bool, error) {
+	data, err := r.Metrics(ctx)
+	if err != nil {
+		return metricdata.Metrics{}, false, err
+	}
+
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name == name {
+				return m, true, nil
+			}
+		}
+	}
+	return metricdata.Metrics{}, false, nil
+}
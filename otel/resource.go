@@ -0,0 +1,149 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"sort"
+
+	"github.com/alfin-efendy/helper-go/config/model"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// k8sEnv maps the resource attributes to the env vars set from the downward API, the first one set is used
+var k8sEnv = []struct {
+	key  attribute.Key
+	envs []string
+}{
+	{semconv.K8SClusterNameKey, []string{"K8S_CLUSTER_NAME"}},
+	{semconv.K8SNamespaceNameKey, []string{"K8S_NAMESPACE_NAME", "POD_NAMESPACE"}},
+	{semconv.K8SNodeNameKey, []string{"K8S_NODE_NAME", "NODE_NAME"}},
+	{semconv.K8SPodNameKey, []string{"K8S_POD_NAME", "POD_NAME"}},
+	{semconv.K8SPodUIDKey, []string{"K8S_POD_UID", "POD_UID"}},
+	{semconv.K8SContainerNameKey, []string{"K8S_CONTAINER_NAME", "CONTAINER_NAME"}},
+	{semconv.K8SDeploymentNameKey, []string{"K8S_DEPLOYMENT_NAME"}},
+}
+
+// newResource describes the service, attributes of the config take precedence over the detected ones
+func newResource(ctx context.Context, conf *model.Config) (*resource.Resource, error) {
+	var configured string
+	if conf.Otel.Resource != nil {
+		configured = conf.Otel.Resource.InstanceId
+	}
+
+	attrs := []attribute.KeyValue{
+		// the service name used to display traces in backends
+		semconv.ServiceName(conf.App.Name),
+		semconv.ServiceInstanceID(instanceId(configured)),
+	}
+
+	if version := serviceVersion(conf.App.Version); version != "" {
+		attrs = append(attrs, semconv.ServiceVersion(version))
+	}
+	if conf.App.Mode != "" {
+		attrs = append(attrs, semconv.DeploymentEnvironment(conf.App.Mode))
+	}
+
+	for _, k8s := range k8sEnv {
+		if value := firstEnv(k8s.envs...); value != "" {
+			attrs = append(attrs, k8s.key.String(value))
+		}
+	}
+
+	if conf.Otel.Resource != nil {
+		attrs = append(attrs, flattenAttributes("", conf.Otel.Resource.Attributes)...)
+	}
+
+	return resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithProcess(),
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+		resource.WithSchemaURL(semconv.SchemaURL),
+		resource.WithAttributes(attrs...),
+	)
+}
+
+// serviceVersion returns the configured version, then the module version or VCS revision of the build
+func serviceVersion(version string) string {
+	if version != "" {
+		return version
+	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+
+	if info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			return setting.Value
+		}
+	}
+
+	return ""
+}
+
+// instanceId returns the configured id, then the pod name, then a random id
+func instanceId(configured string) string {
+	if configured != "" {
+		return configured
+	}
+	if pod := firstEnv("K8S_POD_NAME", "POD_NAME"); pod != "" {
+		return pod
+	}
+	return uuid.NewString()
+}
+
+func firstEnv(envs ...string) string {
+	for _, env := range envs {
+		if value := os.Getenv(env); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// flattenAttributes joins the nested keys with a dot, the config splits dotted keys into nested maps
+func flattenAttributes(prefix string, values map[string]interface{}) []attribute.KeyValue {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var attrs []attribute.KeyValue
+	for _, key := range keys {
+		name := key
+		if prefix != "" {
+			name = prefix + "." + key
+		}
+
+		switch value := values[key].(type) {
+		case map[string]interface{}:
+			attrs = append(attrs, flattenAttributes(name, value)...)
+		case string:
+			attrs = append(attrs, attribute.String(name, value))
+		case bool:
+			attrs = append(attrs, attribute.Bool(name, value))
+		case int:
+			attrs = append(attrs, attribute.Int(name, value))
+		case int64:
+			attrs = append(attrs, attribute.Int64(name, value))
+		case float64:
+			attrs = append(attrs, attribute.Float64(name, value))
+		default:
+			attrs = append(attrs, attribute.String(name, fmt.Sprint(value)))
+		}
+	}
+
+	return attrs
+}
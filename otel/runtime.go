@@ -0,0 +1,173 @@
+package otel
+
+import (
+	"context"
+	"os"
+	"runtime/debug"
+	"runtime/metrics"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	runtimeGoroutines  = "/sched/goroutines:goroutines"
+	runtimeMemoryTotal = "/memory/classes/total:bytes"
+	runtimeMemoryFreed = "/memory/classes/heap/released:bytes"
+	runtimeHeapObjects = "/memory/classes/heap/objects:bytes"
+	runtimeHeapAllocs  = "/gc/heap/allocs:bytes"
+	runtimeHeapGoal    = "/gc/heap/goal:bytes"
+	runtimeGcCycles    = "/gc/cycles/total:gc-cycles"
+)
+
+var cpuModeKey = attribute.Key("cpu.mode")
+
+// runtimeCollector reads the Go runtime and the process once per collection for all its instruments
+type runtimeCollector struct {
+	mu      sync.Mutex
+	samples []metrics.Sample
+	gcStats debug.GCStats
+	lastGc  int64
+
+	goroutines   metric.Int64ObservableUpDownCounter
+	memoryUsed   metric.Int64ObservableUpDownCounter
+	heapUsed     metric.Int64ObservableUpDownCounter
+	allocated    metric.Int64ObservableCounter
+	gcGoal       metric.Int64ObservableUpDownCounter
+	gcCount      metric.Int64ObservableCounter
+	gcPauseTime  metric.Float64ObservableCounter
+	gcPause      metric.Float64Histogram
+	cpuTime      metric.Float64ObservableCounter
+	openFds      metric.Int64ObservableUpDownCounter
+	openFdsLimit metric.Int64ObservableUpDownCounter
+}
+
+// initRuntimeMetrics registers the goroutine, memory, GC, CPU and file descriptor metrics of the process
+func initRuntimeMetrics(meter metric.Meter) error {
+	c := &runtimeCollector{}
+	for _, name := range []string{runtimeGoroutines, runtimeMemoryTotal, runtimeMemoryFreed, runtimeHeapObjects, runtimeHeapAllocs, runtimeHeapGoal, runtimeGcCycles} {
+		c.samples = append(c.samples, metrics.Sample{Name: name})
+	}
+
+	var err error
+	if c.goroutines, err = meter.Int64ObservableUpDownCounter("go.goroutine.count",
+		metric.WithDescription("Count of live goroutines"),
+		metric.WithUnit("{goroutine}"),
+	); err != nil {
+		return err
+	}
+	if c.memoryUsed, err = meter.Int64ObservableUpDownCounter("go.memory.used",
+		metric.WithDescription("Memory used by the Go runtime"),
+		metric.WithUnit("By"),
+	); err != nil {
+		return err
+	}
+	if c.heapUsed, err = meter.Int64ObservableUpDownCounter("go.memory.heap.used",
+		metric.WithDescription("Heap memory occupied by live and not yet swept objects"),
+		metric.WithUnit("By"),
+	); err != nil {
+		return err
+	}
+	if c.allocated, err = meter.Int64ObservableCounter("go.memory.allocated",
+		metric.WithDescription("Memory allocated to the heap by the application"),
+		metric.WithUnit("By"),
+	); err != nil {
+		return err
+	}
+	if c.gcGoal, err = meter.Int64ObservableUpDownCounter("go.memory.gc.goal",
+		metric.WithDescription("Heap size target for the end of the GC cycle"),
+		metric.WithUnit("By"),
+	); err != nil {
+		return err
+	}
+	if c.gcCount, err = meter.Int64ObservableCounter("go.gc.count",
+		metric.WithDescription("Completed GC cycles"),
+		metric.WithUnit("{gc_cycle}"),
+	); err != nil {
+		return err
+	}
+	if c.gcPauseTime, err = meter.Float64ObservableCounter("go.gc.pause.time",
+		metric.WithDescription("Total time the GC stopped the world"),
+		metric.WithUnit("s"),
+	); err != nil {
+		return err
+	}
+	if c.gcPause, err = meter.Float64Histogram("go.gc.pause.duration",
+		metric.WithDescription("Duration of the GC stop the world pauses"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.00001, 0.00005, 0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1),
+	); err != nil {
+		return err
+	}
+	if c.cpuTime, err = meter.Float64ObservableCounter("process.cpu.time",
+		metric.WithDescription("CPU time of the process by mode"),
+		metric.WithUnit("s"),
+	); err != nil {
+		return err
+	}
+	if c.openFds, err = meter.Int64ObservableUpDownCounter("process.open_file_descriptor.count",
+		metric.WithDescription("Count of file descriptors opened by the process"),
+		metric.WithUnit("{file_descriptor}"),
+	); err != nil {
+		return err
+	}
+	if c.openFdsLimit, err = meter.Int64ObservableUpDownCounter("process.open_file_descriptor.limit",
+		metric.WithDescription("Maximum file descriptors the process may open"),
+		metric.WithUnit("{file_descriptor}"),
+	); err != nil {
+		return err
+	}
+
+	_, err = meter.RegisterCallback(c.observe,
+		c.goroutines, c.memoryUsed, c.heapUsed, c.allocated, c.gcGoal, c.gcCount,
+		c.gcPauseTime, c.cpuTime, c.openFds, c.openFdsLimit,
+	)
+	return err
+}
+
+func (c *runtimeCollector) observe(ctx context.Context, o metric.Observer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Unlike runtime.ReadMemStats these do not stop the world
+	metrics.Read(c.samples)
+	values := make(map[string]int64, len(c.samples))
+	for _, sample := range c.samples {
+		if sample.Value.Kind() == metrics.KindUint64 {
+			values[sample.Name] = int64(sample.Value.Uint64())
+		}
+	}
+
+	o.ObserveInt64(c.goroutines, values[runtimeGoroutines])
+	o.ObserveInt64(c.memoryUsed, values[runtimeMemoryTotal]-values[runtimeMemoryFreed])
+	o.ObserveInt64(c.heapUsed, values[runtimeHeapObjects])
+	o.ObserveInt64(c.allocated, values[runtimeHeapAllocs])
+	o.ObserveInt64(c.gcGoal, values[runtimeHeapGoal])
+	o.ObserveInt64(c.gcCount, values[runtimeGcCycles])
+
+	debug.ReadGCStats(&c.gcStats)
+	o.ObserveFloat64(c.gcPauseTime, c.gcStats.PauseTotal.Seconds())
+
+	// Pause holds the most recent pauses first, pauses older than its length are lost
+	pauses := int(min(c.gcStats.NumGC-c.lastGc, int64(len(c.gcStats.Pause))))
+	for _, pause := range c.gcStats.Pause[:pauses] {
+		c.gcPause.Record(ctx, pause.Seconds())
+	}
+	c.lastGc = c.gcStats.NumGC
+
+	if user, system, ok := cpuTime(); ok {
+		o.ObserveFloat64(c.cpuTime, user, metric.WithAttributes(cpuModeKey.String("user")))
+		o.ObserveFloat64(c.cpuTime, system, metric.WithAttributes(cpuModeKey.String("system")))
+	}
+
+	// Only Linux lists the descriptors of the process
+	if entries, err := os.ReadDir("/proc/self/fd"); err == nil {
+		o.ObserveInt64(c.openFds, int64(len(entries)))
+	}
+	if limit, ok := openFdsLimit(); ok {
+		o.ObserveInt64(c.openFdsLimit, limit)
+	}
+
+	return nil
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package otel
+
+// cpuTime is not reported outside of unix
+func cpuTime() (float64, float64, bool) {
+	return 0, 0, false
+}
+
+func openFdsLimit() (int64, bool) {
+	return 0, false
+}
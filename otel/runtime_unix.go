@@ -0,0 +1,28 @@
+//go:build unix
+
+package otel
+
+import (
+	"syscall"
+	"time"
+)
+
+// cpuTime returns the user and system CPU seconds of the process
+func cpuTime() (float64, float64, bool) {
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+		return 0, 0, false
+	}
+
+	user := time.Duration(usage.Utime.Nano())
+	system := time.Duration(usage.Stime.Nano())
+	return user.Seconds(), system.Seconds(), true
+}
+
+func openFdsLimit() (int64, bool) {
+	var limit syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &limit); err != nil {
+		return 0, false
+	}
+	return int64(limit.Cur), true
+}
@@ -0,0 +1,65 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Span returns the underlying span
+func (w *SpanWrapper) Span() trace.Span {
+	return w.span
+}
+
+func (w *SpanWrapper) SetName(name string) {
+	w.span.SetName(name)
+}
+
+func (w *SpanWrapper) SetAttributes(attrs ...attribute.KeyValue) {
+	w.span.SetAttributes(attrs...)
+}
+
+func (w *SpanWrapper) AddEvent(name string, attrs ...attribute.KeyValue) {
+	w.span.AddEvent(name, trace.WithAttributes(attrs...))
+}
+
+// RecordError adds the error as an event and marks the span as failed, a nil error is ignored
+func (w *SpanWrapper) RecordError(err error, attrs ...attribute.KeyValue) {
+	if err == nil {
+		return
+	}
+
+	w.span.RecordError(err, trace.WithAttributes(attrs...))
+	w.span.SetStatus(codes.Error, err.Error())
+}
+
+// TraceID returns the trace id in hex, it is empty when the span is not recorded
+func (w *SpanWrapper) TraceID() string {
+	sc := w.span.SpanContext()
+	if !sc.HasTraceID() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
+
+// TraceFunc runs fn in a span named name, the error returned by fn is recorded on the span.
+// A panic is recorded with its stack trace and raised again once the span is ended.
+func TraceFunc(ctx context.Context, name string, fn func(ctx context.Context) error, opts ...trace.SpanStartOption) (err error) {
+	ctx, span := otelInstance.Trace(ctx, name, opts...)
+	defer func() {
+		if r := recover(); r != nil {
+			span.span.RecordError(fmt.Errorf("panic: %v", r), trace.WithStackTrace(true))
+			span.span.SetStatus(codes.Error, fmt.Sprint(r))
+			span.End()
+			panic(r)
+		}
+
+		span.RecordError(err)
+		span.End()
+	}()
+
+	return fn(ctx)
+}
@@ -0,0 +1,174 @@
+package restapi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/alfin-efendy/helper-go/config"
+	"github.com/alfin-efendy/helper-go/database"
+	"github.com/alfin-efendy/helper-go/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	idempotencyHeader         = "Idempotency-Key"
+	idempotencyReplayedHeader = "Idempotent-Replayed"
+	idempotencyKeyPrefix      = "idempotency:"
+
+	defaultIdempotencyTTL         = 24 * time.Hour
+	defaultIdempotencyLockTimeout = 30 * time.Second
+)
+
+// idempotencyRecord is the state stored in Redis for a single Idempotency-Key
+type idempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	Completed   bool   `json:"completed"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// isUnsafeMethod reports whether the method may change server state
+func isUnsafeMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// idempotencyFingerprint identifies the request payload bound to an Idempotency-Key
+func idempotencyFingerprint(ctx *gin.Context, payload []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(ctx.Request.Method))
+	hash.Write([]byte(ctx.Request.URL.Path))
+	hash.Write([]byte(ctx.Request.URL.RawQuery))
+	hash.Write(payload)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// idempotencyScope identifies the caller owning an Idempotency-Key, so callers sending the same key do not share responses.
+// The middleware runs before AuthMiddleware, the credential is hashed instead of trusting the subject it claims.
+func idempotencyScope(ctx *gin.Context) string {
+	credential, err := ctx.Cookie("access_token")
+	if err != nil || credential == "" {
+		credential = ctx.GetHeader("Authorization")
+	}
+
+	if credential == "" {
+		return "ip:" + ctx.ClientIP()
+	}
+
+	sum := sha256.Sum256([]byte(credential))
+	return "auth:" + hex.EncodeToString(sum[:])
+}
+
+// idempotencyMiddleware replays the stored response for requests
+// retried with the same Idempotency-Key header by the same caller.
+func idempotencyMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		conf := config.Config.Server.RestAPI
+		key := ctx.GetHeader(idempotencyHeader)
+		redisClient := database.GetRedisClient()
+
+		if conf == nil || conf.Idempotency == nil || redisClient == nil || key == "" || !isUnsafeMethod(ctx.Request.Method) {
+			ctx.Next()
+			return
+		}
+
+		ttl := defaultIdempotencyTTL
+		if conf.Idempotency.TTL > 0 {
+			ttl = conf.Idempotency.TTL
+		}
+
+		lockTimeout := defaultIdempotencyLockTimeout
+		if conf.Idempotency.LockTimeout > 0 {
+			lockTimeout = conf.Idempotency.LockTimeout
+		}
+
+		// Read the request body to bind it to the key
+		payload, err := io.ReadAll(ctx.Request.Body)
+		if err == nil {
+			ctx.Request.Body = io.NopCloser(bytes.NewBuffer(payload))
+		}
+
+		thisCtx := ctx.Request.Context()
+		redisKey := idempotencyKeyPrefix + idempotencyScope(ctx) + ":" + ctx.Request.Method + ":" + ctx.Request.URL.Path + ":" + key
+		fingerprint := idempotencyFingerprint(ctx, payload)
+
+		pending, _ := json.Marshal(idempotencyRecord{Fingerprint: fingerprint})
+
+		acquired, err := redisClient.SetNX(thisCtx, redisKey, pending, lockTimeout).Result()
+		if err != nil {
+			logger.Error(thisCtx, err, "Failed to acquire idempotency key")
+			ctx.Next()
+			return
+		}
+
+		if !acquired {
+			var record idempotencyRecord
+
+			data, err := redisClient.Get(thisCtx, redisKey).Bytes()
+			if err != nil && !errors.Is(err, redis.Nil) {
+				logger.Error(thisCtx, err, "Failed to read idempotency key")
+			}
+
+			if err == nil {
+				err = json.Unmarshal(data, &record)
+			}
+
+			switch {
+			case err != nil:
+				ctx.JSON(http.StatusConflict, gin.H{"message": "Request with the same Idempotency-Key is in progress"})
+			case record.Fingerprint != fingerprint:
+				ctx.JSON(http.StatusUnprocessableEntity, gin.H{"message": "Idempotency-Key was already used with a different request"})
+			case !record.Completed:
+				ctx.JSON(http.StatusConflict, gin.H{"message": "Request with the same Idempotency-Key is in progress"})
+			default:
+				ctx.Header(idempotencyReplayedHeader, "true")
+				ctx.Data(record.Status, record.ContentType, record.Body)
+			}
+
+			ctx.Abort()
+			return
+		}
+
+		// Reuse the body captured by the logger middleware when available
+		responseWriter, ok := ctx.Writer.(*ResponseWriter)
+		if !ok {
+			responseWriter = &ResponseWriter{body: bytes.NewBufferString(""), ResponseWriter: ctx.Writer}
+			ctx.Writer = responseWriter
+		}
+		start := responseWriter.body.Len()
+
+		ctx.Next()
+
+		// Let the client retry requests that failed on the server side
+		if ctx.Writer.Status() >= http.StatusInternalServerError {
+			if err := redisClient.Del(thisCtx, redisKey).Err(); err != nil {
+				logger.Error(thisCtx, err, "Failed to release idempotency key")
+			}
+			return
+		}
+
+		completed, _ := json.Marshal(idempotencyRecord{
+			Fingerprint: fingerprint,
+			Completed:   true,
+			Status:      ctx.Writer.Status(),
+			ContentType: ctx.Writer.Header().Get("Content-Type"),
+			Body:        responseWriter.body.Bytes()[start:],
+		})
+
+		if err := redisClient.Set(thisCtx, redisKey, completed, ttl).Err(); err != nil {
+			logger.Error(thisCtx, err, "Failed to store idempotency response")
+		}
+	}
+}
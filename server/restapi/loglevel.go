@@ -0,0 +1,86 @@
+package restapi
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/alfin-efendy/helper-go/logger"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap/zapcore"
+)
+
+const defaultLogLevelPath = "/_admin/log/level"
+
+type logLevelRequest struct {
+	Level string `json:"level"`
+	// Name limits the change to a named logger and its children
+	Name string `json:"name"`
+	// Duration reverts the change after it elapses, e.g. "15m"
+	Duration string `json:"duration"`
+	// Reset removes the override of the named logger
+	Reset bool `json:"reset"`
+}
+
+type logLevelResponse struct {
+	Level     string            `json:"level"`
+	Overrides map[string]string `json:"overrides"`
+}
+
+func currentLogLevel() logLevelResponse {
+	overrides := make(map[string]string)
+	for name, lvl := range logger.GetNamedLevels() {
+		overrides[name] = lvl.String()
+	}
+
+	return logLevelResponse{
+		Level:     logger.GetLevel().String(),
+		Overrides: overrides,
+	}
+}
+
+func getLogLevelHandler(ctx *gin.Context) {
+	SetData(ctx, currentLogLevel())
+}
+
+func setLogLevelHandler(ctx *gin.Context) {
+	var request logLevelRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if request.Name != "" && request.Reset {
+		logger.ResetNamedLevel(request.Name)
+		SetData(ctx, currentLogLevel())
+		return
+	}
+
+	var lvl zapcore.Level
+	if err := lvl.UnmarshalText([]byte(request.Level)); request.Level == "" || err != nil {
+		SetRawResponse(ctx, http.StatusBadRequest, "Invalid log level")
+		return
+	}
+
+	var revertAfter time.Duration
+	if request.Duration != "" {
+		var err error
+		if revertAfter, err = time.ParseDuration(request.Duration); err != nil {
+			SetRawResponse(ctx, http.StatusBadRequest, "Invalid duration")
+			return
+		}
+	}
+
+	if request.Name != "" {
+		logger.SetNamedLevel(request.Name, lvl, revertAfter)
+	} else {
+		logger.SetLevel(lvl, revertAfter)
+	}
+
+	logger.Warnw(ctx.Request.Context(), "Log level changed",
+		"level", lvl.String(),
+		"name", request.Name,
+		"duration", revertAfter.String(),
+	)
+
+	SetData(ctx, currentLogLevel())
+}
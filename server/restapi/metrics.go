@@ -0,0 +1,117 @@
+package restapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/alfin-efendy/helper-go/config"
+	"github.com/alfin-efendy/helper-go/otel"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	requestDurationMetric  = "http.server.request.duration"
+	activeRequestsMetric   = "http.server.active_requests"
+	requestBodySizeMetric  = "http.server.request.body.size"
+	responseBodySizeMetric = "http.server.response.body.size"
+
+	httpMethodKey      = attribute.Key("http.request.method")
+	httpRouteKey       = attribute.Key("http.route")
+	httpStatusCodeKey  = attribute.Key("http.response.status_code")
+	httpStatusClassKey = attribute.Key("http.response.status_class")
+	urlSchemeKey       = attribute.Key("url.scheme")
+	errorTypeKey       = attribute.Key("error.type")
+)
+
+// durationBuckets are the boundaries advised by the HTTP semantic conventions
+var durationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5, 0.75, 1, 2.5, 5, 7.5, 10}
+
+var knownMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true, http.MethodPut: true,
+	http.MethodPatch: true, http.MethodDelete: true, http.MethodConnect: true, http.MethodOptions: true,
+	http.MethodTrace: true,
+}
+
+// metricsMiddleware records the rate, errors and duration of requests per route
+func metricsMiddleware() gin.HandlerFunc {
+	if !metricsEnabled() {
+		return func(ctx *gin.Context) {
+			ctx.Next()
+		}
+	}
+
+	// Register the units and buckets before the instruments are first used
+	registerCtx := context.Background()
+	otel.AddHistogram(registerCtx, requestDurationMetric, "s", durationBuckets...)
+	otel.AddUpDownCounter(registerCtx, activeRequestsMetric, "{request}")
+	otel.AddInt64Histogram(registerCtx, requestBodySizeMetric, "By")
+	otel.AddInt64Histogram(registerCtx, responseBodySizeMetric, "By")
+
+	return func(ctx *gin.Context) {
+		if !isNotScrape(ctx.Request) {
+			ctx.Next()
+			return
+		}
+
+		start := time.Now()
+		reqCtx := ctx.Request.Context()
+
+		method := ctx.Request.Method
+		if !knownMethods[method] {
+			method = "_OTHER"
+		}
+
+		scheme := "http"
+		if ctx.Request.TLS != nil {
+			scheme = "https"
+		}
+
+		activeAttrs := metric.WithAttributes(httpMethodKey.String(method), urlSchemeKey.String(scheme))
+		otel.UpDownCount(reqCtx, activeRequestsMetric, 1, activeAttrs)
+		defer otel.UpDownCount(reqCtx, activeRequestsMetric, -1, activeAttrs)
+
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		attrs := []attribute.KeyValue{
+			httpMethodKey.String(method),
+			urlSchemeKey.String(scheme),
+			httpStatusCodeKey.Int(status),
+			httpStatusClassKey.String(fmt.Sprintf("%dxx", status/100)),
+		}
+		// Unmatched requests have no route, leaving it out keeps the cardinality bounded
+		if route := ctx.FullPath(); route != "" {
+			attrs = append(attrs, httpRouteKey.String(route))
+		}
+		if status >= http.StatusInternalServerError {
+			attrs = append(attrs, errorTypeKey.String(strconv.Itoa(status)))
+		}
+		opt := metric.WithAttributes(attrs...)
+
+		otel.Record(reqCtx, requestDurationMetric, time.Since(start).Seconds(), opt)
+
+		if size := ctx.Request.ContentLength; size >= 0 {
+			otel.RecordInt64(reqCtx, requestBodySizeMetric, size, opt)
+		}
+		if size := ctx.Writer.Size(); size >= 0 {
+			otel.RecordInt64(reqCtx, responseBodySizeMetric, int64(size), opt)
+		}
+	}
+}
+
+// metricsEnabled reports whether otel.metric is on and restAPI.metrics is not false
+func metricsEnabled() bool {
+	conf := config.Config
+	if !conf.Otel.Metric {
+		return false
+	}
+	if restAPI := conf.Server.RestAPI; restAPI != nil && restAPI.Metrics != nil && !*restAPI.Metrics {
+		return false
+	}
+	return true
+}
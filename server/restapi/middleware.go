@@ -9,15 +9,28 @@ import (
 	"strings"
 	"time"
 
+	"github.com/alfin-efendy/helper-go/audit"
 	"github.com/alfin-efendy/helper-go/config"
 	"github.com/alfin-efendy/helper-go/logger"
+	"github.com/alfin-efendy/helper-go/otel"
 	"github.com/alfin-efendy/helper-go/token"
 	"github.com/gin-gonic/gin"
-	"go.uber.org/zap/zapcore"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.uber.org/zap"
 
 	helperCtx "github.com/alfin-efendy/helper-go/context"
 )
 
+const (
+	requestIdHeader = "X-Request-Id"
+	skipLoggingStr  = "skipLogging"
+)
+
+// defaultLogSkipPaths are never logged by the request logger
+var defaultLogSkipPaths = []string{"/_health"}
+
 // ResponseWriter is a custom response writer to capture the response body
 type ResponseWriter struct {
 	gin.ResponseWriter
@@ -36,10 +49,29 @@ func loggerMiddleware() gin.HandlerFunc {
 		// Start timer
 		start := time.Now()
 
-		// Read the request body
-		payload, err := io.ReadAll(ctx.Request.Body)
-		if err == nil {
-			ctx.Request.Body = io.NopCloser(bytes.NewBuffer(payload))
+		// Store request scoped values read by the logger
+		requestId := ctx.GetHeader(requestIdHeader)
+		if requestId == "" {
+			requestId = uuid.NewString()
+		}
+		ctx.Header(requestIdHeader, requestId)
+
+		thisCtx := helperCtx.SetRequestId(ctx.Request.Context(), requestId)
+		thisCtx = helperCtx.SetRoute(thisCtx, ctx.FullPath())
+		thisCtx = helperCtx.SetClientIP(thisCtx, ctx.ClientIP())
+		ctx.Request = ctx.Request.WithContext(thisCtx)
+
+		redactor := logger.GetRedactor()
+		requestContentType := ctx.ContentType()
+
+		// Read the request body, uploads and binary bodies are not buffered
+		var payload []byte
+		if !redactor.SkipContentType(requestContentType) {
+			var err error
+			payload, err = io.ReadAll(ctx.Request.Body)
+			if err == nil {
+				ctx.Request.Body = io.NopCloser(bytes.NewBuffer(payload))
+			}
 		}
 
 		// Create a new body buffer
@@ -49,28 +81,66 @@ func loggerMiddleware() gin.HandlerFunc {
 		// Process request
 		ctx.Next()
 
+		if ctx.GetBool(skipLoggingStr) || isLogSkipPath(ctx) {
+			return
+		}
+
 		// Stop timer
 		end := time.Now()
 		latency := end.Sub(start)
 
-		msg := fmt.Sprintf("[%v] [%v] %s %s", latency, ctx.Writer.Status(), ctx.Request.Method, ctx.Request.RequestURI)
+		msg := fmt.Sprintf("[%v] [%v] %s %s", latency, ctx.Writer.Status(), ctx.Request.Method, ctx.Request.URL.Path)
 
 		// Log details
 		logger.Info(
 			ctx.Request.Context(),
 			msg,
-			zapcore.Field{Key: "Method", Type: zapcore.StringType, String: ctx.Request.Method},
-			zapcore.Field{Key: "Query", Type: zapcore.StringType, String: ctx.Request.URL.RawQuery},
-			zapcore.Field{Key: "Payload", Type: zapcore.ByteStringType, Interface: payload},
-			zapcore.Field{Key: "Status", Type: zapcore.Int64Type, Integer: int64(ctx.Writer.Status())},
-			zapcore.Field{Key: "Response", Type: zapcore.ByteStringType, Interface: responseWriter.body.Bytes()},
-			zapcore.Field{Key: "Latency", Type: zapcore.StringType, String: latency.String()},
-			zapcore.Field{Key: "ClientIP", Type: zapcore.StringType, String: ctx.ClientIP()},
-			zapcore.Field{Key: "UserAgent", Type: zapcore.StringType, String: ctx.GetHeader("User-Agent")},
+			zap.String("Method", ctx.Request.Method),
+			zap.String("Query", redactor.Query(ctx.Request.URL.RawQuery)),
+			zap.Any("Headers", redactor.Headers(ctx.Request.Header)),
+			zap.String("Payload", redactPayload(redactor, requestContentType, payload, ctx.Request.ContentLength)),
+			zap.Int("Status", ctx.Writer.Status()),
+			zap.String("Response", redactor.Body(ctx.Writer.Header().Get("Content-Type"), responseWriter.body.Bytes())),
+			zap.String("Latency", latency.String()),
+			zap.String("ClientIP", ctx.ClientIP()),
+			zap.String("UserAgent", ctx.GetHeader("User-Agent")),
 		)
 	}
 }
 
+// SkipLogging excludes a route from the request logger, e.g. for metrics scrapes
+func SkipLogging() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Set(skipLoggingStr, true)
+		ctx.Next()
+	}
+}
+
+func isLogSkipPath(ctx *gin.Context) bool {
+	paths := defaultLogSkipPaths
+	if conf := config.Config.Server.RestAPI; conf != nil {
+		paths = append(paths, conf.LogSkipPaths...)
+	}
+	if path := otel.PrometheusPath(); path != "" {
+		paths = append(paths, path)
+	}
+
+	for _, path := range paths {
+		if path == ctx.FullPath() || path == ctx.Request.URL.Path {
+			return true
+		}
+	}
+	return false
+}
+
+// redactPayload describes request bodies that were not buffered instead of logging them
+func redactPayload(redactor *logger.Redactor, contentType string, payload []byte, contentLength int64) string {
+	if redactor.SkipContentType(contentType) {
+		return fmt.Sprintf("[omitted %s, %d bytes]", contentType, contentLength)
+	}
+	return redactor.Body(contentType, payload)
+}
+
 // CORSMiddleware adds CORS headers to the response.
 // It is a middleware function for Gin framework.
 func corsMiddleware() gin.HandlerFunc {
@@ -131,6 +201,12 @@ func AuthMiddleware(permission string) gin.HandlerFunc {
 
 			// Check authorization header
 			if !strings.HasPrefix(authorization, "Bearer ") {
+				audit.Record(ctx.Request.Context(), audit.AuditEvent{
+					Action:   audit.ActionAuthenticate,
+					Resource: ctx.FullPath(),
+					Outcome:  audit.OutcomeFailure,
+					Reason:   "missing access token",
+				})
 				ctx.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
 				ctx.Abort()
 				return
@@ -140,7 +216,7 @@ func AuthMiddleware(permission string) gin.HandlerFunc {
 			accessToken = strings.TrimPrefix(authorization, "Bearer ")
 		}
 
-		dataAccess, err := token.TokenValidation(ctx, accessToken, "access", true)
+		dataAccess, err := token.TokenValidation(ctx.Request.Context(), accessToken, "access", true)
 
 		if err != nil {
 			ctx.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
@@ -162,6 +238,14 @@ func AuthMiddleware(permission string) gin.HandlerFunc {
 			}
 
 			if !hasPermission {
+				audit.Record(ctx.Request.Context(), audit.AuditEvent{
+					ActorId:  dataAccess.Subject,
+					Action:   audit.ActionAuthorize,
+					Resource: ctx.FullPath(),
+					Outcome:  audit.OutcomeDenied,
+					Reason:   fmt.Sprintf("missing permission %s", permission),
+					Metadata: map[string]interface{}{"method": ctx.Request.Method, "permission": permission},
+				})
 				ctx.JSON(http.StatusForbidden, gin.H{"message": "Access Denied"})
 				ctx.Abort()
 				return
@@ -170,17 +254,69 @@ func AuthMiddleware(permission string) gin.HandlerFunc {
 
 		ctx.Set("issuer", dataAccess.Issuer)
 		ctx.Set("subject", dataAccess.Subject)
+
+		// The verified token replaces the forwarded user id in the context and its baggage.
+		// It carries no name or realm, the forwarded ones are kept unless they describe another user.
+		thisCtx := ctx.Request.Context()
+		if forwarded := helperCtx.GetUserId(thisCtx); forwarded != "" && forwarded != dataAccess.Subject {
+			thisCtx = helperCtx.SetFullName(thisCtx, "")
+			thisCtx = helperCtx.SetRealm(thisCtx, "")
+		}
+		thisCtx = helperCtx.SetSubject(thisCtx, dataAccess.Subject)
+		thisCtx = helperCtx.SetUserId(thisCtx, dataAccess.Subject)
+		ctx.Request = ctx.Request.WithContext(thisCtx)
+
+		ctx.Next()
+	}
+}
+
+// HeaderToContext reads the identity forwarded by internal services in the X- headers.
+// Register it only on routes that external clients cannot reach, AuthMiddleware replaces it with the token identity.
+func HeaderToContext() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		thisCtx := ctx.Request.Context()
+
+		if userId := ctx.GetHeader("X-User-Id"); userId != "" {
+			thisCtx = helperCtx.SetUserId(thisCtx, userId)
+		}
+		if fullName := ctx.GetHeader("X-Full-Name"); fullName != "" {
+			thisCtx = helperCtx.SetFullName(thisCtx, fullName)
+		}
+		if realm := ctx.GetHeader("X-Realm"); realm != "" {
+			thisCtx = helperCtx.SetRealm(thisCtx, realm)
+		}
+		ctx.Request = ctx.Request.WithContext(thisCtx)
 		ctx.Next()
 	}
 }
 
-func headerToContext() gin.HandlerFunc {
+// baggageToContext fills the identity missing from the X- headers with the W3C baggage of a trusted caller,
+// it is read even when OpenTelemetry is disabled
+func baggageToContext() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		thisCtx := ctx.Request.Context()
+		bag := baggage.FromContext(propagation.Baggage{}.Extract(thisCtx, propagation.HeaderCarrier(ctx.Request.Header)))
+
+		if value := bag.Member(helperCtx.UserIdBaggageKey).Value(); value != "" && helperCtx.GetUserId(thisCtx) == "" {
+			thisCtx = helperCtx.SetUserId(thisCtx, value)
+		}
+		if value := bag.Member(helperCtx.FullNameBaggageKey).Value(); value != "" && helperCtx.GetFullName(thisCtx) == "" {
+			thisCtx = helperCtx.SetFullName(thisCtx, value)
+		}
+		if value := bag.Member(helperCtx.RealmBaggageKey).Value(); value != "" && helperCtx.GetRealm(thisCtx) == "" {
+			thisCtx = helperCtx.SetRealm(thisCtx, value)
+		}
+
+		ctx.Request = ctx.Request.WithContext(thisCtx)
+		ctx.Next()
+	}
+}
 
-		helperCtx.SetUserId(thisCtx, ctx.GetHeader("X-User-Id"))
-		helperCtx.SetFullName(thisCtx, ctx.GetHeader("X-Full-Name"))
-		helperCtx.SetRealm(thisCtx, ctx.GetHeader("X-Realm"))
+// dropIdentityBaggage removes the identity members of the baggage sent by an untrusted caller,
+// so the context getters never fall back to them
+func dropIdentityBaggage() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Request = ctx.Request.WithContext(helperCtx.DropIdentityBaggage(ctx.Request.Context()))
 		ctx.Next()
 	}
 }
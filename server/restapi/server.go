@@ -16,6 +16,7 @@ import (
 	"github.com/alfin-efendy/helper-go/database"
 	"github.com/alfin-efendy/helper-go/logger"
 	"github.com/alfin-efendy/helper-go/otel"
+	"github.com/alfin-efendy/helper-go/session"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
@@ -30,19 +31,34 @@ func Init(ctx context.Context) {
 
 	Server = gin.Default()
 
-	Server.Use(
-		otelgin.Middleware(config.Config.App.Name),
+	middlewares := []gin.HandlerFunc{
+		otelgin.Middleware(config.Config.App.Name, otelgin.WithFilter(isNotScrape)),
 		traceRequest(),
+		metricsMiddleware(),
 		gin.Recovery(),
 		gzip.Gzip(gzip.DefaultCompression),
+	}
+
+	// Any client can send the identity headers and baggage, they are only read when the server is not reachable directly
+	if conf := config.Config.Server.RestAPI; conf != nil && conf.TrustIdentityHeaders {
+		middlewares = append(middlewares, HeaderToContext(), baggageToContext())
+	} else {
+		middlewares = append(middlewares, dropIdentityBaggage())
+	}
+
+	middlewares = append(middlewares,
 		loggerMiddleware(),
 		corsMiddleware(),
 		helmetMiddleware(),
+		session.Middleware(),
+		idempotencyMiddleware(),
 		paginationRequest(),
 		errorResponse(),
 		successResponse(),
 	)
 
+	Server.Use(middlewares...)
+
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		v.RegisterValidation("isUrl", isUrl)
 		v.RegisterValidation("isActiveEmail", isActiveEmail)
@@ -50,6 +66,33 @@ func Init(ctx context.Context) {
 	}
 
 	Server.GET("/_health", gin.WrapH(healthz()))
+
+	// Expose metrics to prometheus when it does not have its own port
+	if handler := otel.MetricsHandler(); handler != nil {
+		Server.GET(otel.PrometheusPath(), gin.WrapH(handler))
+	}
+
+	if conf := config.Config.Server.RestAPI; conf != nil && conf.LogLevel != nil {
+		// Without a permission any authenticated user could change the log level
+		if conf.LogLevel.Permission == "" {
+			logger.Fatal(ctx, fmt.Errorf("restAPI.logLevel.permission is required"), "❌ Failed to register log level routes")
+			return
+		}
+
+		path := conf.LogLevel.Path
+		if path == "" {
+			path = defaultLogLevelPath
+		}
+
+		Server.GET(path, AuthMiddleware(conf.LogLevel.Permission), getLogLevelHandler)
+		Server.PUT(path, AuthMiddleware(conf.LogLevel.Permission), setLogLevelHandler)
+	}
+}
+
+// isNotScrape keeps prometheus scrapes out of the traces
+func isNotScrape(r *http.Request) bool {
+	path := otel.PrometheusPath()
+	return path == "" || r.URL.Path != path
 }
 
 func addChecker(name string, f func(ctx context.Context) error) {
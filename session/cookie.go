@@ -0,0 +1,154 @@
+package session
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alfin-efendy/helper-go/config"
+	"github.com/gin-gonic/gin"
+)
+
+const accessTokenCookie = "access_token"
+
+var ErrInvalidCookie = errors.New("invalid session cookie")
+
+// codec signs and optionally encrypts session ids stored in cookies
+type codec struct {
+	secret []byte
+	aead   cipher.AEAD
+}
+
+func newCodec(secret string, encryptionKey string) (*codec, error) {
+	c := &codec{secret: []byte(secret)}
+
+	if encryptionKey == "" {
+		return c, nil
+	}
+
+	// Encryption key is base64 encoded like the token keys, 16, 24 or 32 bytes for AES
+	key, err := base64.StdEncoding.DecodeString(encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.aead, err = cipher.NewGCM(block); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *codec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// Encode returns the cookie value for a session id
+func (c *codec) Encode(id string) (string, error) {
+	payload := []byte(id)
+
+	if c.aead != nil {
+		nonce := make([]byte, c.aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return "", err
+		}
+		payload = c.aead.Seal(nonce, nonce, payload, nil)
+	}
+
+	encoding := base64.RawURLEncoding
+	return encoding.EncodeToString(payload) + "." + encoding.EncodeToString(c.sign(payload)), nil
+}
+
+// Decode verifies the cookie value and returns the session id
+func (c *codec) Decode(value string) (string, error) {
+	encoding := base64.RawURLEncoding
+
+	parts := strings.Split(value, ".")
+	if len(parts) != 2 {
+		return "", ErrInvalidCookie
+	}
+
+	payload, err := encoding.DecodeString(parts[0])
+	if err != nil {
+		return "", ErrInvalidCookie
+	}
+
+	signature, err := encoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(signature, c.sign(payload)) {
+		return "", ErrInvalidCookie
+	}
+
+	if c.aead != nil {
+		nonceSize := c.aead.NonceSize()
+		if len(payload) < nonceSize {
+			return "", ErrInvalidCookie
+		}
+
+		payload, err = c.aead.Open(nil, payload[:nonceSize], payload[nonceSize:], nil)
+		if err != nil {
+			return "", ErrInvalidCookie
+		}
+	}
+
+	return string(payload), nil
+}
+
+func sameSite(mode string) http.SameSite {
+	switch strings.ToLower(mode) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
+
+// SetCookie sets an http only cookie scoped to app.domain, maxAge is in seconds
+func SetCookie(ctx *gin.Context, name string, value string, maxAge int) {
+	path := "/"
+	secure := true
+	mode := ""
+
+	if conf := config.Config.Session; conf != nil {
+		if conf.Path != "" {
+			path = conf.Path
+		}
+		if conf.Secure != nil {
+			secure = *conf.Secure
+		}
+		mode = conf.SameSite
+	}
+
+	ctx.SetSameSite(sameSite(mode))
+	ctx.SetCookie(name, value, maxAge, path, config.Config.App.Domain, secure, true)
+}
+
+// ClearCookie expires the cookie in the browser
+func ClearCookie(ctx *gin.Context, name string) {
+	SetCookie(ctx, name, "", -1)
+}
+
+// SetTokenCookie stores the access token in the cookie read by AuthMiddleware,
+// the response carries the CSRF token the next unsafe requests must send with the cookie
+func SetTokenCookie(ctx *gin.Context, accessToken string, expiresAt time.Time) {
+	SetCookie(ctx, accessTokenCookie, accessToken, int(time.Until(expiresAt).Seconds()))
+
+	if err := exposeCSRFToken(ctx); err != nil {
+		ctx.Error(err)
+	}
+}
@@ -0,0 +1,101 @@
+package session
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+
+	"github.com/alfin-efendy/helper-go/server"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	csrfHeader    = "X-CSRF-Token"
+	csrfFormField = "_csrf"
+)
+
+// CSRFToken returns the CSRF token of the session, generating it on first use
+func CSRFToken(ctx *gin.Context) (string, error) {
+	session := Get(ctx)
+	if session == nil {
+		return "", nil
+	}
+
+	if session.CSRFToken == "" {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return "", err
+		}
+
+		session.CSRFToken = base64.RawURLEncoding.EncodeToString(b)
+		session.changed = true
+	}
+
+	return session.CSRFToken, nil
+}
+
+// exposeCSRFToken sends the CSRF token of the session in the X-CSRF-Token response header, generating it on first use
+func exposeCSRFToken(ctx *gin.Context) error {
+	token, err := CSRFToken(ctx)
+	if err != nil || token == "" {
+		return err
+	}
+
+	ctx.Header(csrfHeader, token)
+	return nil
+}
+
+// CSRFMiddleware verifies the CSRF token of unsafe requests made with a session or access token cookie.
+// Safe requests of an existing session or an access token cookie get the token in the X-CSRF-Token response header,
+// anonymous requests do not so they do not persist a session each.
+func CSRFMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		session := Get(ctx)
+		if session == nil {
+			ctx.Next()
+			return
+		}
+
+		switch ctx.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+			if session.isNew && !hasCookie(ctx, accessTokenCookie) {
+				ctx.Next()
+				return
+			}
+
+			if err := exposeCSRFToken(ctx); err != nil {
+				ctx.Error(err)
+				ctx.AbortWithStatusJSON(http.StatusInternalServerError, server.Response{Message: "Internal Server Error"})
+				return
+			}
+
+			ctx.Next()
+			return
+		}
+
+		// Requests without a session or access token cookie carry no ambient credentials to abuse
+		if session.isNew && !hasCookie(ctx, accessTokenCookie) {
+			ctx.Next()
+			return
+		}
+
+		token := ctx.GetHeader(csrfHeader)
+		if token == "" {
+			token = ctx.PostForm(csrfFormField)
+		}
+
+		if session.CSRFToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(session.CSRFToken)) != 1 {
+			ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid CSRF token"})
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+func hasCookie(ctx *gin.Context, name string) bool {
+	_, err := ctx.Request.Cookie(name)
+	return err == nil
+}
@@ -0,0 +1,272 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/alfin-efendy/helper-go/config"
+	"github.com/alfin-efendy/helper-go/database"
+	"github.com/alfin-efendy/helper-go/logger"
+	"github.com/alfin-efendy/helper-go/otel"
+	"github.com/alfin-efendy/helper-go/server"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	sessionStr = "session"
+
+	defaultName = "session_id"
+	defaultTTL  = 24 * time.Hour
+)
+
+var manager *sessionManager
+
+// Session is the server side state bound to a session cookie
+type Session struct {
+	ID        string                 `json:"id"`
+	Values    map[string]interface{} `json:"values"`
+	CSRFToken string                 `json:"csrfToken,omitempty"`
+	CreatedAt time.Time              `json:"createdAt"`
+	ExpiresAt time.Time              `json:"expiresAt"`
+
+	isNew   bool
+	changed bool
+}
+
+func (s *Session) Get(key string) interface{} {
+	return s.Values[key]
+}
+
+func (s *Session) Set(key string, value interface{}) {
+	s.Values[key] = value
+	s.changed = true
+}
+
+func (s *Session) Delete(key string) {
+	delete(s.Values, key)
+	s.changed = true
+}
+
+type sessionManager struct {
+	store Store
+	codec *codec
+	name  string
+	ttl   time.Duration
+}
+
+func Init(ctx context.Context) {
+	ctx, span := otel.Trace(ctx)
+	defer span.End()
+
+	conf := config.Config.Session
+	if conf == nil {
+		return
+	}
+
+	if conf.Secret == "" {
+		logger.Fatal(ctx, errors.New("session secret is empty"), "❌ Failed to initialize session")
+		return
+	}
+
+	codec, err := newCodec(conf.Secret, conf.EncryptionKey)
+	if err != nil {
+		logger.Fatal(ctx, err, "❌ Failed to parse session encryption key")
+		return
+	}
+
+	var store Store
+	switch conf.Store {
+	case "", "redis":
+		redisClient := database.GetRedisClient()
+		if redisClient == nil {
+			logger.Fatal(ctx, errors.New("redis client is not initialized"), "❌ Failed to initialize redis session store")
+			return
+		}
+		store = NewRedisStore(redisClient)
+	case "memory":
+		store = NewMemoryStore()
+	default:
+		logger.Fatal(ctx, fmt.Errorf("session store %s is not supported", conf.Store), "❌ Failed unsupported session store")
+		return
+	}
+
+	manager = &sessionManager{
+		store: store,
+		codec: codec,
+		name:  defaultName,
+		ttl:   defaultTTL,
+	}
+
+	if conf.Name != "" {
+		manager.name = conf.Name
+	}
+	if conf.TTL > 0 {
+		manager.ttl = conf.TTL
+	}
+
+	logger.Info(ctx, "✅ Session store initialized")
+}
+
+// UseStore replaces the session store, e.g. with NewMemoryStore in tests
+func UseStore(store Store) {
+	if manager != nil {
+		manager.store = store
+	}
+}
+
+func newId() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func (m *sessionManager) newSession() (*Session, error) {
+	id, err := newId()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	return &Session{
+		ID:        id,
+		Values:    make(map[string]interface{}),
+		CreatedAt: now,
+		ExpiresAt: now.Add(m.ttl),
+		isNew:     true,
+	}, nil
+}
+
+// load returns the session referenced by the cookie, or a new one
+func (m *sessionManager) load(ctx *gin.Context) (*Session, error) {
+	if value, err := ctx.Cookie(m.name); err == nil {
+		if id, err := m.codec.Decode(value); err == nil {
+			session, err := m.store.Get(ctx.Request.Context(), id)
+			if err == nil {
+				if session.Values == nil {
+					session.Values = make(map[string]interface{})
+				}
+				return session, nil
+			}
+			if !errors.Is(err, ErrNotFound) {
+				return nil, err
+			}
+		}
+	}
+
+	return m.newSession()
+}
+
+func (m *sessionManager) setCookie(ctx *gin.Context, session *Session) error {
+	value, err := m.codec.Encode(session.ID)
+	if err != nil {
+		return err
+	}
+
+	SetCookie(ctx, m.name, value, int(m.ttl.Seconds()))
+	return nil
+}
+
+// Middleware loads the session into the gin context and slides its expiration on every request
+func Middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if manager == nil {
+			ctx.Next()
+			return
+		}
+
+		thisCtx := ctx.Request.Context()
+
+		session, err := manager.load(ctx)
+		if err != nil {
+			ctx.Error(err)
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, server.Response{Message: "Internal Server Error"})
+			return
+		}
+
+		// Cookie must be written before the handler writes the body
+		session.ExpiresAt = time.Now().UTC().Add(manager.ttl)
+		if err := manager.setCookie(ctx, session); err != nil {
+			ctx.Error(err)
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, server.Response{Message: "Internal Server Error"})
+			return
+		}
+
+		ctx.Set(sessionStr, session)
+		ctx.Next()
+
+		// New sessions are only persisted once something is stored in them
+		if session.isNew && !session.changed {
+			return
+		}
+
+		if err := manager.store.Save(thisCtx, session, manager.ttl); err != nil {
+			logger.Error(thisCtx, err, "Failed to save session")
+		}
+	}
+}
+
+// Get returns the session of the request, nil when the session middleware is not used
+func Get(ctx *gin.Context) *Session {
+	if session, exists := ctx.Get(sessionStr); exists {
+		return session.(*Session)
+	}
+	return nil
+}
+
+// Regenerate moves the session to a new id and CSRF token, call it after login to prevent session fixation
+func Regenerate(ctx *gin.Context) error {
+	session := Get(ctx)
+	if session == nil {
+		return errors.New("session middleware is not used")
+	}
+
+	if !session.isNew {
+		if err := manager.store.Delete(ctx.Request.Context(), session.ID); err != nil {
+			return err
+		}
+	}
+
+	id, err := newId()
+	if err != nil {
+		return err
+	}
+
+	session.ID = id
+	session.changed = true
+
+	if err := manager.setCookie(ctx, session); err != nil {
+		return err
+	}
+
+	// A token seen before the login must not stay valid after it
+	session.CSRFToken = ""
+	return exposeCSRFToken(ctx)
+}
+
+// Destroy deletes the session and expires its cookie
+func Destroy(ctx *gin.Context) error {
+	session := Get(ctx)
+	if session == nil {
+		return nil
+	}
+
+	if err := manager.store.Delete(ctx.Request.Context(), session.ID); err != nil {
+		return err
+	}
+
+	ClearCookie(ctx, manager.name)
+
+	// Prevent the middleware from saving it again
+	session.Values = make(map[string]interface{})
+	session.isNew = true
+	session.changed = false
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const redisKeyPrefix = "session:"
+
+var ErrNotFound = errors.New("session not found")
+
+// Store persists sessions by their raw id
+type Store interface {
+	Get(ctx context.Context, id string) (*Session, error)
+	Save(ctx context.Context, session *Session, ttl time.Duration) error
+	Delete(ctx context.Context, id string) error
+}
+
+type redisStore struct {
+	client *redis.Client
+}
+
+func NewRedisStore(client *redis.Client) Store {
+	return &redisStore{client: client}
+}
+
+func (s *redisStore) Get(ctx context.Context, id string) (*Session, error) {
+	data, err := s.client.Get(ctx, redisKeyPrefix+id).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	session := &Session{}
+	if err := json.Unmarshal(data, session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (s *redisStore) Save(ctx context.Context, session *Session, ttl time.Duration) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	return s.client.Set(ctx, redisKeyPrefix+session.ID, data, ttl).Err()
+}
+
+func (s *redisStore) Delete(ctx context.Context, id string) error {
+	return s.client.Del(ctx, redisKeyPrefix+id).Err()
+}
+
+// memoryStore keeps sessions in process, it is meant for tests and single instance setups
+type memoryStore struct {
+	mu       sync.Mutex
+	sessions map[string]memoryEntry
+}
+
+type memoryEntry struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+func NewMemoryStore() Store {
+	return &memoryStore{sessions: make(map[string]memoryEntry)}
+}
+
+func (s *memoryStore) Get(_ context.Context, id string) (*Session, error) {
+	s.mu.Lock()
+	entry, ok := s.sessions[id]
+	if ok && time.Now().After(entry.expiresAt) {
+		delete(s.sessions, id)
+		ok = false
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	session := &Session{}
+	if err := json.Unmarshal(entry.data, session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (s *memoryStore) Save(_ context.Context, session *Session, ttl time.Duration) error {
+	// Store a copy so later changes are only visible after the next save
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.sessions[session.ID] = memoryEntry{data: data, expiresAt: time.Now().Add(ttl)}
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *memoryStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	delete(s.sessions, id)
+	s.mu.Unlock()
+
+	return nil
+}
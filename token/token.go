@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/alfin-efendy/helper-go/audit"
 	"github.com/alfin-efendy/helper-go/config"
 	"github.com/alfin-efendy/helper-go/database"
 	"github.com/golang-jwt/jwt/v5"
@@ -11,9 +12,8 @@ import (
 )
 
 // signToken is a helper private function that signs a JWT token and stores it in Redis.
-func signToken(ctx context.Context, id, issuer, subject, keyPrivate string, expiredHour int, ability []string) (string, time.Time, error) {
+func signToken(ctx context.Context, id, issuer, subject, keyPrivate string, tokenExpiredDuration time.Duration, ability []string) (string, time.Time, error) {
 	// Generate token expired time
-	tokenExpiredDuration := time.Duration(expiredHour) * time.Hour
 	tokenExpired := time.Now().UTC().Add(tokenExpiredDuration)
 
 	// Generate JWT token
@@ -50,28 +50,38 @@ func TokenGenerate(ctx context.Context, subject string, ability []string) (strin
 	accessId := uuid.New().String()
 	config := config.Config
 	issuer := config.App.Name
-	accessExpired := config.Token.AccessExpireHour
+	accessExpired := config.Token.AccessExpire
+	if accessExpired == 0 {
+		accessExpired = time.Duration(config.Token.AccessExpireHour) * time.Hour
+	}
 	accessPrivateKey := config.Token.AccessPrivateKey
 
 	// Generate access token
 	accessToken, accessTokenExpired, err := signToken(ctx, accessId, issuer, subject, accessPrivateKey, accessExpired, ability)
 
 	if err != nil {
+		recordTokenIssue(ctx, subject, accessId, ability, err)
 		return "", time.Time{}, "", time.Time{}, err
 	}
 
 	// Generate refresh token id
 	refreshId := uuid.New().String()
-	refreshExpired := config.Token.RefreshExpireHour
+	refreshExpired := config.Token.RefreshExpire
+	if refreshExpired == 0 {
+		refreshExpired = time.Duration(config.Token.RefreshExpireHour) * time.Hour
+	}
 	refreshPrivateKey := config.Token.RefreshPrivateKey
 
 	// Generate refresh token
 	refreshToken, refreshTokenExpired, err := signToken(ctx, refreshId, issuer, accessId, refreshPrivateKey, refreshExpired, []string{})
 
 	if err != nil {
+		recordTokenIssue(ctx, subject, accessId, ability, err)
 		return "", time.Time{}, "", time.Time{}, err
 	}
 
+	recordTokenIssue(ctx, subject, accessId, ability, nil)
+
 	return accessToken, accessTokenExpired, refreshToken, refreshTokenExpired, nil
 }
 
@@ -92,6 +102,7 @@ func TokenValidation(ctx context.Context, token, tokenType string, validationExp
 		if !validationExpired && err.Error() == "token has invalid claims: token is expired" {
 			return dataToken, nil
 		}
+		recordTokenValidation(ctx, "", tokenType, err)
 		return nil, err
 	}
 
@@ -99,8 +110,37 @@ func TokenValidation(ctx context.Context, token, tokenType string, validationExp
 
 	// Check refresh token in Redis
 	if err := redis.Get(ctx, dataToken.Subject).Err(); err != nil {
+		recordTokenValidation(ctx, dataToken.Subject, tokenType, err)
 		return nil, err
 	}
 
 	return dataToken, nil
 }
+
+// recordTokenIssue adds the issuance of the token pair of the subject to the audit trail
+func recordTokenIssue(ctx context.Context, subject, accessId string, ability []string, err error) {
+	event := audit.AuditEvent{
+		ActorId:    subject,
+		Action:     audit.ActionTokenIssue,
+		Resource:   "token",
+		ResourceId: accessId,
+		Metadata:   map[string]interface{}{"ability": ability},
+	}
+	if err != nil {
+		event.Outcome = audit.OutcomeFailure
+		event.Reason = err.Error()
+	}
+
+	audit.Record(ctx, event)
+}
+
+// recordTokenValidation adds a rejected token to the audit trail, the subject is empty when the token cannot be parsed
+func recordTokenValidation(ctx context.Context, subject, tokenType string, err error) {
+	audit.Record(ctx, audit.AuditEvent{
+		ActorId:  subject,
+		Action:   audit.ActionTokenValidate,
+		Resource: tokenType + "_token",
+		Outcome:  audit.OutcomeFailure,
+		Reason:   err.Error(),
+	})
+}